@@ -11,6 +11,8 @@ type PErrors struct {
 	Message string
 	Details string
 	Err     error
+	// Pos locates the error when it happened while decoding, nil otherwise
+	Pos *Position
 }
 
 const (
@@ -22,6 +24,14 @@ const (
 	ErrCodeTxTypeNotSupported       = 6
 	ErrCodeInvalidSig               = 7
 	ErrCodeInvalidPkb               = 8
+	ErrCodeInvalidAuthChainId       = 9
+	ErrCodeInvalidAuthNonce         = 10
+	ErrCodeSizeLeadingZero          = 11
+	ErrCodeNonCanonicalSize         = 12
+	ErrCodeIntLeadingZero           = 13
+	ErrCodeUnexpectedEOF            = 14
+	ErrCodeDecode                   = 15
+	ErrCodeTxsRootMismatch          = 16
 )
 
 var (
@@ -33,6 +43,14 @@ var (
 	ErrTxTypeNotSupported       = NewPError(ErrCodeTxTypeNotSupported, "tx type not supported")
 	ErrInvalidSig               = NewPError(ErrCodeInvalidSig, "invalid signature")
 	ErrInvalidPkb               = NewPError(ErrCodeInvalidPkb, "invalid public key")
+	ErrInvalidAuthChainId       = NewPError(ErrCodeInvalidAuthChainId, "invalid authorization chain id")
+	ErrInvalidAuthNonce         = NewPError(ErrCodeInvalidAuthNonce, "invalid authorization nonce")
+	ErrSizeLeadingZero          = NewPError(ErrCodeSizeLeadingZero, "non-canonical size, leading zero bytes")
+	ErrNonCanonicalSize         = NewPError(ErrCodeNonCanonicalSize, "non-canonical size, long form used for size < 56")
+	ErrIntLeadingZero           = NewPError(ErrCodeIntLeadingZero, "non-canonical integer, leading zero bytes")
+	ErrUnexpectedEOF            = NewPError(ErrCodeUnexpectedEOF, "unexpected end of input")
+	ErrDecode                   = NewPError(ErrCodeDecode, "decode error")
+	ErrTxsRootMismatch          = NewPError(ErrCodeTxsRootMismatch, "transactions root mismatch")
 )
 
 // NewPError creates a new PErrors
@@ -45,10 +63,27 @@ func NewPError(code int, message string) *PErrors {
 
 // Error implements the error interface
 func (e *PErrors) Error() string {
-	if e.Err != nil {
-		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	msg := e.Message + e.details()
+	if e.Pos != nil {
+		return fmt.Sprintf("%s: %s", e.Pos, msg)
 	}
-	return e.Message
+	return msg
+}
+
+// details returns the details of the error followed by the ones of the errors it wraps. The message of the error
+// wrapped by WithMessage is not repeated, it is the same one.
+func (e *PErrors) details() string {
+	var details string
+	if e.Details != "" {
+		details = ": " + e.Details
+	}
+	if e.Err == nil {
+		return details
+	}
+	if inner, ok := e.Err.(*PErrors); ok && inner.Code == e.Code && inner.Message == e.Message {
+		return details + inner.details()
+	}
+	return fmt.Sprintf("%s: %v", details, e.Err)
 }
 
 // Unwrap returns the underlying error
@@ -93,6 +128,7 @@ func (e *PErrors) WithError(err error) *PErrors {
 		Message: e.Message,
 		Details: e.Details,
 		Err:     err,
+		Pos:     e.Pos,
 	}
 }
 
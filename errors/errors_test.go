@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+func TestPErrors_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"message", ErrUnexpectedLength, "unexpected length"},
+		{"details", ErrUnexpectedLength.WithMessagef("tx payload ends at %d instead of %d", 5, 7),
+			"unexpected length: tx payload ends at 5 instead of 7"},
+		{"nested details", ErrUnexpectedLength.WithMessage("outer").WithMessage("inner"),
+			"unexpected length: inner: outer"},
+		{"wrapped error", ErrUnexpectedEOF.WithError(io.EOF), "unexpected end of input: EOF"},
+		{"position", AtOffset(ErrUnexpectedLength.WithMessagef("tx payload ends at %d instead of %d", 5, 7), 3),
+			"at offset 3: unexpected length: tx payload ends at 5 instead of 7"},
+		{"full position", WithTxIndex(WithTxType(WithField(AtOffset(ErrIntLeadingZero, 4211), "gasFeeCap"), 2), 17),
+			"tx[17] type=2 field=gasFeeCap at offset 4211: non-canonical integer, leading zero bytes"},
+		{"other error", AtOffset(fmt.Errorf("bad input"), 1), "at offset 1: decode error: bad input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.err.Error())
+		})
+	}
+}
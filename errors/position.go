@@ -0,0 +1,130 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Position locates a decoding error: the byte offset in the decoded input, the index of the tx within the packet,
+// the tx type and the field being decoded. TxIndex and TxType are -1 when unknown.
+type Position struct {
+	Offset  uint64
+	TxIndex int
+	TxType  int
+	Field   string
+}
+
+// String formats the position as tx[17] type=2 field=gasFeeCap at offset 4211
+func (p *Position) String() string {
+	var sb strings.Builder
+	if p.TxIndex >= 0 {
+		fmt.Fprintf(&sb, "tx[%d] ", p.TxIndex)
+	}
+	if p.TxType >= 0 {
+		fmt.Fprintf(&sb, "type=%d ", p.TxType)
+	}
+	if p.Field != "" {
+		fmt.Fprintf(&sb, "field=%s ", p.Field)
+	}
+	fmt.Fprintf(&sb, "at offset %d", p.Offset)
+	return sb.String()
+}
+
+// positioned returns a copy of err as a PErrors with a position, so shared errors are never modified.
+// Errors that are not PErrors are wrapped, io.EOF as ErrUnexpectedEOF and the rest as ErrDecode.
+func positioned(err error) *PErrors {
+	var p PErrors
+	switch e := err.(type) {
+	case *PErrors:
+		p = *e
+	default:
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			p = *ErrUnexpectedEOF.WithError(err)
+		} else {
+			p = *ErrDecode.WithError(err)
+		}
+	}
+	if p.Pos != nil {
+		pos := *p.Pos
+		p.Pos = &pos
+	} else {
+		p.Pos = &Position{TxIndex: -1, TxType: -1}
+	}
+	return &p
+}
+
+// AtOffset sets the byte offset where the decoding error happened. Returns nil if err is nil
+func AtOffset(err error, offset uint64) error {
+	if err == nil {
+		return nil
+	}
+	p := positioned(err)
+	p.Pos.Offset = offset
+	return p
+}
+
+// ShiftOffset adds delta to the offset of the decoding error, used when the input was decoded from a sub slice.
+// Returns nil if err is nil
+func ShiftOffset(err error, delta uint64) error {
+	if err == nil {
+		return nil
+	}
+	p := positioned(err)
+	p.Pos.Offset += delta
+	return p
+}
+
+// WithField prepends field to the field of the decoding error, so nested fields are reported as
+// accessList[2].storageKeys[0]. Returns nil if err is nil
+func WithField(err error, field string) error {
+	if err == nil {
+		return nil
+	}
+	p := positioned(err)
+	switch {
+	case p.Pos.Field == "":
+		p.Pos.Field = field
+	case strings.HasPrefix(p.Pos.Field, "["):
+		p.Pos.Field = field + p.Pos.Field
+	default:
+		p.Pos.Field = field + "." + p.Pos.Field
+	}
+	return p
+}
+
+// WithTxType sets the type of the tx being decoded when the error happened, if not already set.
+// Returns nil if err is nil
+func WithTxType(err error, txType int) error {
+	if err == nil {
+		return nil
+	}
+	p := positioned(err)
+	if p.Pos.TxType < 0 {
+		p.Pos.TxType = txType
+	}
+	return p
+}
+
+// WithTxIndex sets the index within the packet of the tx being decoded when the error happened, if not already set.
+// Returns nil if err is nil
+func WithTxIndex(err error, index int) error {
+	if err == nil {
+		return nil
+	}
+	p := positioned(err)
+	if p.Pos.TxIndex < 0 {
+		p.Pos.TxIndex = index
+	}
+	return p
+}
+
+// GetPosition returns the position of a decoding error, nil if the error has none
+func GetPosition(err error) *Position {
+	var p *PErrors
+	if errors.As(err, &p) {
+		return p.Pos
+	}
+	return nil
+}
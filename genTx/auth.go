@@ -0,0 +1,106 @@
+package genTx
+
+import (
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/pool"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"math"
+	"math/big"
+)
+
+// SetCodeAuthorizationMagic is the prefix byte of the payload signed by the authority of a SetCodeAuthorization (EIP-7702)
+var SetCodeAuthorizationMagic = byte(0x05)
+
+// AuthorizationSigHash returns the hash signed by the authority: keccak256(0x05 || rlp([chain_id, address, nonce]))
+func AuthorizationSigHash(auth *types.SetCodeAuthorization) common.Hash {
+	hasher := pool.GetHasher()
+	buffer := pool.GetRLPBuffer()
+	defer pool.PutHasher(hasher)
+	defer pool.PutRLPBuffer(buffer)
+
+	buffer.WriteByte(SetCodeAuthorizationMagic)
+	length := CalculateRLUint256ValueLength(&auth.ChainID) + AddressRLPLength + CalculateRLP64ValueLength(auth.Nonce)
+	WriteListLength(buffer, length)
+	WriteRLPBytes(buffer, auth.ChainID.Bytes())
+	buffer.WriteByte(EncodedAddressRLPLength)
+	buffer.Write(auth.Address.Bytes())
+	WriteRLPUint64(buffer, auth.Nonce)
+
+	var h common.Hash
+	buffer.WriteTo(hasher)
+	hasher.Read(h[:])
+	return h
+}
+
+// RecoverAuthority recovers the address that signed the provided SetCodeAuthorization.
+// Signatures with a high s value are rejected as EIP-7702 requires.
+func RecoverAuthority(auth *types.SetCodeAuthorization) (common.Address, error) {
+	if !validateSignatureValuesUint256(auth.V, &auth.R, &auth.S, true) {
+		return common.Address{}, errors.ErrInvalidSig
+	}
+	sighash := AuthorizationSigHash(auth)
+	// encode the signature in uncompressed format
+	var sig [crypto.SignatureLength]byte
+	auth.R.WriteToSlice(sig[:32])
+	auth.S.WriteToSlice(sig[32:64])
+	sig[64] = auth.V
+	// recover the public key from the signature
+	pub, err := crypto.Ecrecover(sighash[:], sig[:])
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(pub) == 0 || pub[0] != 4 {
+		return common.Address{}, errors.ErrInvalidPkb
+	}
+	hasher := pool.GetHasher()
+	defer pool.PutHasher(hasher)
+	hasher.Write(pub[1:])
+	var h common.Hash
+	hasher.Read(h[:])
+	return common.BytesToAddress(h[12:]), nil
+}
+
+// ValidateAuthorization checks the stateless rules of EIP-7702 for an authorization:
+// - chain id must be 0 or equal to the provided chain id
+// - nonce must be lower than 2^64-1
+// Signature values are checked when recovering the authority.
+func ValidateAuthorization(auth *types.SetCodeAuthorization, chainId *big.Int) error {
+	if !auth.ChainID.IsZero() {
+		// chain ids are uint256 values, compared without truncating them
+		var txChainId uint256.Int
+		if chainId == nil || chainId.Sign() < 0 || txChainId.SetFromBig(chainId) || !txChainId.Eq(&auth.ChainID) {
+			return errors.ErrInvalidAuthChainId.WithMessagef("got %s", auth.ChainID.Dec())
+		}
+	}
+	if auth.Nonce == math.MaxUint64 {
+		return errors.ErrInvalidAuthNonce
+	}
+	return nil
+}
+
+// Authority validates the authorization at position i of the AuthList and recovers its authority
+func (tx *CustomTx) Authority(i int) (common.Address, error) {
+	if i < 0 || i >= len(tx.AuthList) {
+		return common.Address{}, errors.ErrUnexpectedLength.WithMessagef("authorization %d out of range", i)
+	}
+	auth := &tx.AuthList[i]
+	err := ValidateAuthorization(auth, tx.ChainID)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return RecoverAuthority(auth)
+}
+
+// Authorities validates and recovers the authority of every entry of the AuthList.
+// Invalid entries get an empty address and their error in the same position of the errors slice.
+func (tx *CustomTx) Authorities() ([]common.Address, []error) {
+	authorities := make([]common.Address, len(tx.AuthList))
+	errs := make([]error, len(tx.AuthList))
+	for i := range tx.AuthList {
+		authorities[i], errs[i] = tx.Authority(i)
+	}
+	return authorities, errs
+}
@@ -0,0 +1,129 @@
+package genTx
+
+import (
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestCustomTx_Authorities(t *testing.T) {
+	privKey, err := getPrivkeyForTests()
+	if err != nil {
+		t.Fatalf("Failed to get private key: %v", err)
+	}
+	authority := crypto.PubkeyToAddress(privKey.PublicKey)
+
+	signAuth := func(chainId uint64, nonce uint64) types.SetCodeAuthorization {
+		auth, err := types.SignSetCode(privKey, types.SetCodeAuthorization{
+			ChainID: *uint256.NewInt(chainId),
+			Address: common.Address{0xde, 0xad},
+			Nonce:   nonce,
+		})
+		if err != nil {
+			t.Fatalf("Failed to sign authorization: %v", err)
+		}
+		return auth
+	}
+	// flipping s into the upper range and v gives a signature for the same key that EIP-7702 rejects
+	highS := signAuth(56, 1)
+	highS.S.Sub(secp256k1NU256, &highS.S)
+	highS.V ^= 1
+
+	tests := []struct {
+		Name      string
+		Auth      types.SetCodeAuthorization
+		WantError error
+	}{
+		{
+			Name: "Test authorization with matching chain id",
+			Auth: signAuth(56, 1),
+		},
+		{
+			Name: "Test authorization with chain id 0",
+			Auth: signAuth(0, 1000),
+		},
+		{
+			Name:      "Test authorization with another chain id",
+			Auth:      signAuth(1, 1),
+			WantError: errors.ErrInvalidAuthChainId,
+		},
+		{
+			Name:      "Test authorization with max nonce",
+			Auth:      signAuth(56, math.MaxUint64),
+			WantError: errors.ErrInvalidAuthNonce,
+		},
+		{
+			Name:      "Test authorization with high s",
+			Auth:      highS,
+			WantError: errors.ErrInvalidSig,
+		},
+	}
+
+	tx := &CustomTx{
+		TxType:  types.SetCodeTxType,
+		ChainID: big.NewInt(56),
+	}
+	for _, tt := range tests {
+		tx.AuthList = append(tx.AuthList, tt.Auth)
+	}
+	authorities, errs := tx.Authorities()
+	assert.Len(t, authorities, len(tests))
+	assert.Len(t, errs, len(tests))
+
+	for i, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if tt.WantError != nil {
+				assert.ErrorIs(t, errs[i], tt.WantError)
+				assert.Equal(t, common.Address{}, authorities[i])
+			} else {
+				assert.NoError(t, errs[i])
+				assert.Equal(t, authority, authorities[i])
+				want, err := tt.Auth.Authority()
+				assert.NoError(t, err)
+				assert.Equal(t, want, authorities[i])
+			}
+		})
+	}
+
+	_, err = tx.Authority(len(tests))
+	assert.ErrorIs(t, err, errors.ErrUnexpectedLength)
+}
+
+func TestValidateAuthorization_ChainId(t *testing.T) {
+	// chain ids that do not fit in 64 bits
+	large := new(big.Int).Lsh(big.NewInt(1), 64)
+	largest := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	tests := []struct {
+		Name      string
+		AuthChain *big.Int
+		TxChain   *big.Int
+		WantError error
+	}{
+		{Name: "matching 2^64", AuthChain: large, TxChain: large},
+		{Name: "matching 2^256-1", AuthChain: largest, TxChain: largest},
+		{Name: "2^64 with tx chain 0", AuthChain: large, TxChain: big.NewInt(0), WantError: errors.ErrInvalidAuthChainId},
+		{Name: "2^64+1 with tx chain 1", AuthChain: new(big.Int).Add(large, big.NewInt(1)), TxChain: big.NewInt(1),
+			WantError: errors.ErrInvalidAuthChainId},
+		{Name: "tx chain larger than 256 bits", AuthChain: big.NewInt(1), TxChain: new(big.Int).Lsh(big.NewInt(1), 256),
+			WantError: errors.ErrInvalidAuthChainId},
+		{Name: "negative tx chain", AuthChain: big.NewInt(1), TxChain: big.NewInt(-1), WantError: errors.ErrInvalidAuthChainId},
+		{Name: "no tx chain", AuthChain: big.NewInt(1), WantError: errors.ErrInvalidAuthChainId},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			auth := &types.SetCodeAuthorization{ChainID: *uint256.MustFromBig(tt.AuthChain), Nonce: 1}
+			err := ValidateAuthorization(auth, tt.TxChain)
+			if tt.WantError != nil {
+				assert.ErrorIs(t, err, tt.WantError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
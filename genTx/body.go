@@ -0,0 +1,258 @@
+package genTx
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/pool"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+	"io"
+	"slices"
+)
+
+// BlockBody is the body of a block: its txs, the rlp bytes of its uncle headers and its withdrawals. Uncles point to
+// the decoded bytes, like the rlp bytes of the txs.
+type BlockBody struct {
+	Transactions []*CustomTx
+	Uncles       [][]byte
+	// Withdrawals is nil when the body has no withdrawals list, as bodies of blocks before Shanghai
+	Withdrawals []*types.Withdrawal
+}
+
+// DecodeBlockBodiesPacket decodes a BlockBodies response, the request id followed by the list of the bodies
+func DecodeBlockBodiesPacket(r *reader.RlpReader) (uint64, []*BlockBody, error) {
+	// read list length
+	_, err := r.ReadListSize()
+	if err != nil {
+		return 0, nil, err
+	}
+	requestID, err := r.DecodeUint64()
+	if err != nil {
+		return 0, nil, errors.WithField(err, "requestId")
+	}
+	bodies, err := DecodeBlockBodies(r)
+	return requestID, bodies, err
+}
+
+// DecodeBlockBodies decodes a list of block bodies, see DecodeBlockBody
+func DecodeBlockBodies(r *reader.RlpReader) ([]*BlockBody, error) {
+	var bodies []*BlockBody
+	listSize, err := r.ReadListSize()
+	if err != nil {
+		return nil, err
+	}
+	cPos := r.Pos()
+	for r.Pos()-cPos < listSize {
+		body, err := DecodeBlockBody(r)
+		if err != nil {
+			return bodies, errors.WithField(err, fmt.Sprintf("[%d]", len(bodies)))
+		}
+		bodies = append(bodies, body)
+	}
+	if r.Pos() != cPos+listSize {
+		err = errors.ErrUnexpectedLength.WithMessagef("list items end at %d instead of %d", r.Pos(), cPos+listSize)
+		return bodies, errors.AtOffset(err, cPos)
+	}
+	return bodies, nil
+}
+
+// DecodeBlockBody decodes a block body, the list of its txs, uncles and optionally withdrawals. Unlike
+// DecodeTxsPacket, txs of unsupported types are an error, since the transactions root cannot be calculated without
+// them.
+func DecodeBlockBody(r *reader.RlpReader) (*BlockBody, error) {
+	start := r.Pos()
+	listSize, err := r.ReadListSize()
+	if err != nil {
+		return nil, err
+	}
+	end := r.Pos() + listSize
+	body := new(BlockBody)
+	if body.Transactions, err = decodeBodyTxs(r); err != nil {
+		return nil, errors.WithField(err, "transactions")
+	}
+	if body.Uncles, err = decodeUncles(r); err != nil {
+		return nil, errors.WithField(err, "uncles")
+	}
+	if r.Pos() < end {
+		if body.Withdrawals, err = decodeWithdrawals(r); err != nil {
+			return nil, errors.WithField(err, "withdrawals")
+		}
+	}
+	if r.Pos() != end {
+		err = errors.ErrUnexpectedLength.WithMessagef("body ends at %d instead of %d", r.Pos(), end)
+		return nil, errors.AtOffset(err, start)
+	}
+	return body, nil
+}
+
+func decodeBodyTxs(r *reader.RlpReader) ([]*CustomTx, error) {
+	txs := make([]*CustomTx, 0)
+	listSize, err := r.ReadListSize()
+	if err != nil {
+		return txs, err
+	}
+	cPos := r.Pos()
+	for r.Pos()-cPos < listSize {
+		start := r.Pos()
+		tx, err := DecodeTx(r)
+		if err != nil {
+			if errors.Is(err, errors.ErrTxTypeNotSupported) {
+				err = errors.AtOffset(err, start)
+			}
+			return txs, errors.WithTxIndex(err, len(txs))
+		}
+		txs = append(txs, tx)
+	}
+	if r.Pos() != cPos+listSize {
+		err = errors.ErrUnexpectedLength.WithMessagef("list items end at %d instead of %d", r.Pos(), cPos+listSize)
+		return txs, errors.AtOffset(err, cPos)
+	}
+	return txs, nil
+}
+
+// decodeUncles returns the rlp bytes of the uncle headers, without decoding them
+func decodeUncles(r *reader.RlpReader) ([][]byte, error) {
+	uncles := make([][]byte, 0)
+	listSize, err := r.ReadListSize()
+	if err != nil {
+		return uncles, err
+	}
+	cPos := r.Pos()
+	for r.Pos()-cPos < listSize {
+		start := r.Pos()
+		headerSize, err := r.ReadListSize()
+		if err != nil {
+			return uncles, errors.WithField(err, fmt.Sprintf("[%d]", len(uncles)))
+		}
+		if err = r.Skip(headerSize); err != nil {
+			return uncles, errors.WithField(errors.AtOffset(io.EOF, start), fmt.Sprintf("[%d]", len(uncles)))
+		}
+		uncles = append(uncles, r.GetBytes(start, r.Pos()))
+	}
+	if r.Pos() != cPos+listSize {
+		err = errors.ErrUnexpectedLength.WithMessagef("list items end at %d instead of %d", r.Pos(), cPos+listSize)
+		return uncles, errors.AtOffset(err, cPos)
+	}
+	return uncles, nil
+}
+
+func decodeWithdrawals(r *reader.RlpReader) ([]*types.Withdrawal, error) {
+	withdrawals := make([]*types.Withdrawal, 0)
+	listSize, err := r.ReadListSize()
+	if err != nil {
+		return withdrawals, err
+	}
+	cPos := r.Pos()
+	for r.Pos()-cPos < listSize {
+		withdrawal, err := DecodeWithdrawal(r)
+		if err != nil {
+			return withdrawals, errors.WithField(err, fmt.Sprintf("[%d]", len(withdrawals)))
+		}
+		withdrawals = append(withdrawals, withdrawal)
+	}
+	if r.Pos() != cPos+listSize {
+		err = errors.ErrUnexpectedLength.WithMessagef("list items end at %d instead of %d", r.Pos(), cPos+listSize)
+		return withdrawals, errors.AtOffset(err, cPos)
+	}
+	return withdrawals, nil
+}
+
+// DecodeWithdrawal decodes a withdrawal: its index, validator index, address and amount in Gwei
+func DecodeWithdrawal(r *reader.RlpReader) (*types.Withdrawal, error) {
+	start := r.Pos()
+	listSize, err := r.ReadListSize()
+	if err != nil {
+		return nil, err
+	}
+	end := r.Pos() + listSize
+	withdrawal := new(types.Withdrawal)
+	if withdrawal.Index, err = r.DecodeUint64(); err != nil {
+		return nil, errors.WithField(err, "index")
+	}
+	if withdrawal.Validator, err = r.DecodeUint64(); err != nil {
+		return nil, errors.WithField(err, "validator")
+	}
+	addressPos := r.Pos()
+	address, err := r.DecodeNextValue()
+	if err != nil {
+		return nil, errors.WithField(err, "address")
+	}
+	if len(address) != common.AddressLength {
+		err = errors.ErrUnexpectedLength.WithMessagef("address length %d", len(address))
+		return nil, errors.WithField(errors.AtOffset(err, addressPos), "address")
+	}
+	withdrawal.Address = common.Address(address)
+	if withdrawal.Amount, err = r.DecodeUint64(); err != nil {
+		return nil, errors.WithField(err, "amount")
+	}
+	if r.Pos() != end {
+		err = errors.ErrUnexpectedLength.WithMessagef("withdrawal ends at %d instead of %d", r.Pos(), end)
+		return nil, errors.AtOffset(err, start)
+	}
+	return withdrawal, nil
+}
+
+// TxsRoot returns the transactions root of the body, see DeriveTxsRoot
+func (b *BlockBody) TxsRoot() (common.Hash, error) {
+	return DeriveTxsRoot(b.Transactions)
+}
+
+// VerifyTxsRoot checks that the txs of the body are the ones of a header with the transactions root txHash.
+// Returns ErrTxsRootMismatch if they are not.
+func (b *BlockBody) VerifyTxsRoot(txHash common.Hash) error {
+	root, err := b.TxsRoot()
+	if err != nil {
+		return err
+	}
+	if root != txHash {
+		return errors.ErrTxsRootMismatch.WithMessagef("got %s, want %s", root, txHash)
+	}
+	return nil
+}
+
+// DeriveTxsRoot returns the root of the Merkle-Patricia trie of the txs, the transactions root of the header of a
+// block with them. It is calculated from the SignedRlpBytes of the txs, encoding the ones that have none, which are
+// left unchanged. Blob txs with a sidecar are included without it, as in blocks.
+func DeriveTxsRoot(txs []*CustomTx) (common.Hash, error) {
+	derivable := slices.Clone(derivableTxs(txs))
+	buffer := pool.GetRLPBuffer()
+	defer pool.PutRLPBuffer(buffer)
+	for i, tx := range txs {
+		if len(tx.SignedRlpBytes) > 0 {
+			continue
+		}
+		// the rlp is saved in a copy of the tx, so the trie is built from it without modifying the txs
+		encoded := *tx
+		buffer.Reset()
+		if err := encoded.EncodeSignedRLP(buffer, true); err != nil {
+			return common.Hash{}, errors.WithTxIndex(err, i)
+		}
+		derivable[i] = &encoded
+	}
+	return types.DeriveSha(derivable, trie.NewStackTrie(nil)), nil
+}
+
+// derivableTxs implements types.DerivableList over the SignedRlpBytes of the txs
+type derivableTxs []*CustomTx
+
+func (txs derivableTxs) Len() int {
+	return len(txs)
+}
+
+// EncodeIndex writes the encoding of the tx that its hash covers, see CalculateSignedHash
+func (txs derivableTxs) EncodeIndex(i int, w *bytes.Buffer) {
+	tx := txs[i]
+	switch {
+	case tx.TxType == types.LegacyTxType:
+		w.Write(tx.SignedRlpBytes)
+	case tx.endBlobTxPayload > 0:
+		// network form of a blob tx, without the sidecar
+		w.WriteByte(tx.TxType)
+		w.Write(tx.SignedRlpBytes[tx.startBlobTxPayload:tx.endBlobTxPayload])
+	default:
+		w.Write(tx.SignedRlpBytes[tx.startTx:])
+	}
+}
@@ -0,0 +1,223 @@
+package genTx
+
+import (
+	"fmt"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+// blocksForTests returns a block of every kind of body: with txs of every type, uncles and withdrawals, without
+// withdrawals as before Shanghai, and empty
+func blocksForTests(t testing.TB) []*types.Block {
+	txs := tx256TxsForTests(t)
+	// blocks hold blob txs without their sidecar
+	for i, tx := range txs {
+		txs[i] = tx.WithoutBlobTxSidecar()
+	}
+	uncle := &types.Header{Number: big.NewInt(9), Difficulty: big.NewInt(1), Extra: []byte("uncle")}
+	withdrawals := []*types.Withdrawal{
+		{Index: 0, Validator: 1, Address: common.Address{0x01}, Amount: 0},
+		{Index: 1 << 40, Validator: 1 << 20, Address: common.Address{0x02}, Amount: 32e9},
+	}
+	bodies := []*types.Body{
+		{Transactions: txs, Uncles: []*types.Header{uncle}, Withdrawals: withdrawals},
+		{Transactions: txs[:1], Uncles: []*types.Header{uncle, uncle}},
+		{Withdrawals: []*types.Withdrawal{}},
+	}
+	blocks := make([]*types.Block, len(bodies))
+	for i, body := range bodies {
+		blocks[i] = types.NewBlock(&types.Header{Number: big.NewInt(10)}, body, nil, trie.NewStackTrie(nil))
+	}
+	return blocks
+}
+
+// compareBlockBodyForTests checks the decoded body against the body of the go-ethereum block
+func compareBlockBodyForTests(t *testing.T, got *BlockBody, want *types.Block) {
+	assert.Len(t, got.Transactions, len(want.Transactions()))
+	for i, tx := range want.Transactions() {
+		compareTxFieldsForTests(t, got.Transactions[i], tx)
+		assert.Equal(t, tx.Hash(), got.Transactions[i].Hash())
+	}
+	assert.Len(t, got.Uncles, len(want.Uncles()))
+	for i, uncle := range want.Uncles() {
+		enc, err := rlp.EncodeToBytes(uncle)
+		assert.NoError(t, err)
+		assert.Equal(t, enc, got.Uncles[i])
+	}
+	assert.Equal(t, want.Withdrawals() == nil, got.Withdrawals == nil)
+	assert.Len(t, got.Withdrawals, len(want.Withdrawals()))
+	for i, withdrawal := range want.Withdrawals() {
+		assert.Equal(t, withdrawal, got.Withdrawals[i])
+	}
+}
+
+func TestDecodeBlockBody(t *testing.T) {
+	for i, block := range blocksForTests(t) {
+		enc, err := rlp.EncodeToBytes(block.Body())
+		assert.NoError(t, err)
+		r := reader.NewStrictReader(enc)
+		body, err := DecodeBlockBody(r)
+		assert.NoError(t, err, "block %d", i)
+		assert.Zero(t, r.Len())
+		compareBlockBodyForTests(t, body, block)
+
+		root, err := body.TxsRoot()
+		assert.NoError(t, err)
+		assert.Equal(t, block.Header().TxHash, root, "block %d", i)
+		assert.NoError(t, body.VerifyTxsRoot(block.Header().TxHash))
+		err = body.VerifyTxsRoot(common.Hash{0x01})
+		assert.ErrorIs(t, err, errors.ErrTxsRootMismatch)
+		assert.EqualError(t, err, fmt.Sprintf("transactions root mismatch: got %s, want %s", root, common.Hash{0x01}))
+
+		// the root of txs without rlp bytes is calculated by encoding them
+		fromFields := make([]*CustomTx, len(body.Transactions))
+		for j, tx := range body.Transactions {
+			fromFields[j] = exportedFieldsForTests(tx)
+		}
+		root, err = DeriveTxsRoot(fromFields)
+		assert.NoError(t, err)
+		assert.Equal(t, block.Header().TxHash, root, "block %d", i)
+		for _, tx := range fromFields {
+			assert.Empty(t, tx.SignedRlpBytes)
+		}
+
+		// a different tx order is a different root
+		if len(body.Transactions) > 1 {
+			body.Transactions[0], body.Transactions[1] = body.Transactions[1], body.Transactions[0]
+			assert.ErrorIs(t, body.VerifyTxsRoot(block.Header().TxHash), errors.ErrTxsRootMismatch)
+		}
+	}
+}
+
+func TestDeriveTxsRoot_NetworkBlobTx(t *testing.T) {
+	// a blob tx as received from the pool, with its sidecar, has the root of the tx in a block
+	withSidecar := newSignedBlobTxForTests(t, 1)
+	packet, err := rlp.EncodeToBytes(types.Transactions{withSidecar})
+	assert.NoError(t, err)
+	txs, err := DecodeTxsPacket(reader.NewReader(packet))
+	assert.NoError(t, err)
+	root, err := DeriveTxsRoot(txs)
+	assert.NoError(t, err)
+	assert.Equal(t, types.DeriveSha(types.Transactions{withSidecar.WithoutBlobTxSidecar()}, trie.NewStackTrie(nil)), root)
+
+	_, err = DeriveTxsRoot([]*CustomTx{{TxType: 0x7f}})
+	assert.ErrorIs(t, err, errors.ErrTxTypeNotSupported)
+}
+
+func TestDecodeBlockBodiesPacket(t *testing.T) {
+	blocks := blocksForTests(t)
+	response := make(eth.BlockBodiesResponse, len(blocks))
+	for i, block := range blocks {
+		response[i] = &eth.BlockBody{
+			Transactions: block.Transactions(),
+			Uncles:       block.Uncles(),
+			Withdrawals:  block.Withdrawals(),
+		}
+	}
+	packet, err := rlp.EncodeToBytes(eth.BlockBodiesPacket{RequestId: 1 << 33, BlockBodiesResponse: response})
+	assert.NoError(t, err)
+
+	r := reader.NewStrictReader(packet)
+	requestID, bodies, err := DecodeBlockBodiesPacket(r)
+	assert.NoError(t, err)
+	assert.Zero(t, r.Len())
+	assert.Equal(t, uint64(1<<33), requestID)
+	assert.Len(t, bodies, len(blocks))
+	for i, block := range blocks {
+		compareBlockBodyForTests(t, bodies[i], block)
+		assert.NoError(t, bodies[i].VerifyTxsRoot(block.Header().TxHash))
+	}
+}
+
+func TestDecodeBlockBody_Errors(t *testing.T) {
+	legacy, err := rlp.EncodeToBytes(tx256TxsForTests(t)[0])
+	assert.NoError(t, err)
+	withdrawal, err := rlp.EncodeToBytes(&types.Withdrawal{Index: 1, Validator: 2, Address: common.Address{0x03}, Amount: 4})
+	assert.NoError(t, err)
+	tests := []struct {
+		name    string
+		data    []byte
+		err     error
+		field   string
+		txIndex int
+	}{
+		{"not a list", []byte{0x80}, errors.ErrNotAList, "", -1},
+		{"unsupported tx type", rlpListForTests(rlpListForTests(legacy, []byte{0x82, 0x7f, 0xc0}), rlpListForTests()),
+			errors.ErrTxTypeNotSupported, "transactions", 1},
+		{"missing uncles", rlpListForTests(rlpListForTests(legacy)), errors.ErrUnexpectedEOF, "uncles", -1},
+		{"uncle not a list", rlpListForTests(rlpListForTests(), rlpListForTests([]byte{0x01})), errors.ErrNotAList, "uncles[0]", -1},
+		{"short withdrawal address", rlpListForTests(rlpListForTests(), rlpListForTests(),
+			rlpListForTests(rlpListForTests([]byte{0x01}, []byte{0x02}, []byte{0x03}, []byte{0x04}))),
+			errors.ErrUnexpectedLength, "withdrawals[0].address", -1},
+		{"withdrawal index longer than 8 bytes", rlpListForTests(rlpListForTests(), rlpListForTests(),
+			rlpListForTests(rlpListForTests([]byte{0x89, 0x01, 0, 0, 0, 0, 0, 0, 0, 0x01}, []byte{0x02}, withdrawal[3:24], []byte{0x04}))),
+			errors.ErrUnexpectedLength, "withdrawals[0].index", -1},
+		{"trailing field", rlpListForTests(rlpListForTests(), rlpListForTests(), rlpListForTests(withdrawal), []byte{0x01}),
+			errors.ErrUnexpectedLength, "", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeBlockBody(reader.NewReader(tt.data))
+			assert.ErrorIs(t, err, tt.err)
+			pos := errors.GetPosition(err)
+			assert.NotNil(t, pos)
+			assert.Equal(t, tt.field, pos.Field)
+			assert.Equal(t, tt.txIndex, pos.TxIndex)
+		})
+	}
+}
+
+func BenchmarkDecodeBlockBody(b *testing.B) {
+	enc, err := rlp.EncodeToBytes(blocksForTests(b)[0].Body())
+	assert.NoError(b, err)
+	b.Run("prlp", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			body, err := DecodeBlockBody(reader.NewReader(enc))
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err = body.TxsRoot(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("geth", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var body types.Body
+			if err := rlp.DecodeBytes(enc, &body); err != nil {
+				b.Fatal(err)
+			}
+			types.DeriveSha(types.Transactions(body.Transactions), trie.NewStackTrie(nil))
+		}
+	})
+}
+
+func FuzzDecodeBlockBody(f *testing.F) {
+	for _, block := range blocksForTests(f) {
+		enc, err := rlp.EncodeToBytes(block.Body())
+		if err != nil {
+			f.Fatalf("Failed to RLP encode body: %v", err)
+		}
+		f.Add(enc)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		body, err := DecodeBlockBody(reader.NewStrictReader(data))
+		if err != nil {
+			return
+		}
+		// the root of every decoded body can be calculated
+		if _, err = body.TxsRoot(); err != nil {
+			t.Fatalf("decoded body without root: %v", err)
+		}
+	})
+}
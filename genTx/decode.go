@@ -1,12 +1,14 @@
 package genTx
 
 import (
+	"fmt"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
 	"github.com/1aBcD1234aBcD1/prlp/reader"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/holiman/uint256"
 	"io"
-	"math/big"
 )
 
 // DecodePoolTxsPacket decodes a list of transactions from the provided RlpReader and returns them as a slice of CustomTx.
@@ -19,12 +21,13 @@ func DecodePoolTxsPacket(r *reader.RlpReader) ([]*CustomTx, error) {
 	}
 	_, err = r.DecodeUint64()
 	if err != nil {
-		return []*CustomTx{}, err
+		return []*CustomTx{}, errors.WithField(err, "requestId")
 	}
 	return DecodeTxsPacket(r)
 }
 
 // DecodeTxsPacket decodes a list of transactions from the provided RlpReader and returns them as a slice of CustomTx.
+// Non-canonical encodings are rejected when the reader is in strict mode, see reader.NewStrictReader.
 // Returns an error if decoding fails.
 func DecodeTxsPacket(r *reader.RlpReader) ([]*CustomTx, error) {
 	var txs []*CustomTx
@@ -34,65 +37,140 @@ func DecodeTxsPacket(r *reader.RlpReader) ([]*CustomTx, error) {
 		return nil, err
 	}
 	cPos := r.Pos()
-	for r.Pos()-cPos < listSize {
-		if r.IsNextValAList() {
-			tx, err := DecodeLegacyTx(r)
-			if err != nil {
-				return txs, err
-			}
-			txs = append(txs, tx)
-		} else {
-			// get current point so we can store the rlpbytes
-			pos := r.Pos()
-			// we already assume that this is another tx type so we just read how many bytes it has
-			valLength, err := r.ReadValueSize()
-			if err != nil {
-				panic(err)
-			}
-			// check that there are enough bytes to read the tx
-			if !r.EnoughBytes(valLength) {
-				return txs, io.EOF
+	for i := 0; r.Pos()-cPos < listSize; i++ {
+		tx, err := DecodeTx(r)
+		if err != nil {
+			if errors.Is(err, errors.ErrTxTypeNotSupported) {
+				continue
 			}
-			// starting point just indicates from which byte from the rlp needs to read for the tx hash
-			startPoint := r.Pos() - pos
-
-			rlpBytes := r.GetBytes(pos, pos+valLength+startPoint)
-			txType, err := r.ReadByte()
-			switch txType {
-			case types.AccessListTxType:
-				{
-					tx, err := DecodeAccessListTx(r, rlpBytes, startPoint)
-					if err != nil {
-						return txs, err
-					}
-					txs = append(txs, tx)
-				}
-			case types.DynamicFeeTxType:
-				{
-					tx, err := DecodeDynamicFeeTx(r, rlpBytes, startPoint)
-					if err != nil {
-						return nil, err
-					}
-					txs = append(txs, tx)
-				}
-			default:
-				// up to this point we have read that it is not a supported tx,
-				// so the next thing to do is read the list length and skip the nbytes
-				txListSize, err := r.ReadListSize()
-				if err != nil {
-					return txs, err
-				}
-				err = r.Skip(txListSize)
-				if err != nil {
-					return txs, err
-				}
+			return txs, errors.WithTxIndex(err, i)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// DecodePoolTxsPacketInto is the same as DecodePoolTxsPacket but decoding into the txs of dst, see
+// DecodeTxsPacketInto
+func DecodePoolTxsPacketInto(r *reader.RlpReader, dst []*CustomTx) ([]*CustomTx, error) {
+	_, err := r.ReadListSize()
+	if err != nil {
+		return dst[:0], err
+	}
+	_, err = r.DecodeUint64()
+	if err != nil {
+		return dst[:0], errors.WithField(err, "requestId")
+	}
+	return DecodeTxsPacketInto(r, dst)
+}
+
+// DecodeTxsPacketInto is the same as DecodeTxsPacket but decoding into the txs of dst with DecodeTxInto, so draining
+// packets into the same slice does not allocate once it is large enough. The returned slice holds the decoded txs
+// and shares the array of dst, whose txs beyond its length are reused too. New txs are only allocated when the array
+// has no tx to reuse.
+func DecodeTxsPacketInto(r *reader.RlpReader, dst []*CustomTx) ([]*CustomTx, error) {
+	txs := dst[:0]
+	// read list length
+	listSize, err := r.ReadListSize()
+	if err != nil {
+		return txs, err
+	}
+	cPos := r.Pos()
+	for i := 0; r.Pos()-cPos < listSize; i++ {
+		tx := nextReusableTx(txs)
+		err = DecodeTxInto(r, tx)
+		if err != nil {
+			if errors.Is(err, errors.ErrTxTypeNotSupported) {
 				continue
 			}
+			return txs, errors.WithTxIndex(err, i)
 		}
+		txs = append(txs, tx)
 	}
 	return txs, nil
 }
 
+// nextReusableTx returns the tx that follows the last one of txs in their array, or a new one if there is none
+func nextReusableTx(txs []*CustomTx) *CustomTx {
+	if len(txs) < cap(txs) {
+		if tx := txs[:len(txs)+1][len(txs)]; tx != nil {
+			return tx
+		}
+	}
+	return new(CustomTx)
+}
+
+// DecodeTx decodes the next transaction of the provided RlpReader, whatever its type is.
+// Returns errors.ErrTxTypeNotSupported, after skipping it, if the tx type is not supported.
+func DecodeTx(r *reader.RlpReader) (*CustomTx, error) {
+	tx := new(CustomTx)
+	if err := DecodeTxInto(r, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// DecodeTxInto is the same as DecodeTx but decoding into tx, which is reset first reusing its big.Ints, address and
+// lists. Values taken from tx before must not be used after calling it. On error tx is left partially decoded.
+func DecodeTxInto(r *reader.RlpReader, tx *CustomTx) error {
+	if r.IsNextValAList() {
+		if err := DecodeLegacyTxInto(r, tx); err != nil {
+			return errors.WithTxType(err, types.LegacyTxType)
+		}
+		return nil
+	}
+	// get current point so we can store the rlpbytes
+	pos := r.Pos()
+	// we already assume that this is another tx type so we just read how many bytes it has
+	valLength, err := r.ReadValueSize()
+	if err != nil {
+		return err
+	}
+	// check that there are enough bytes to read the tx
+	if !r.EnoughBytes(valLength) {
+		return errors.AtOffset(io.EOF, pos)
+	}
+	// starting point just indicates from which byte from the rlp needs to read for the tx hash
+	startPoint := r.Pos() - pos
+
+	rlpBytes := r.GetBytes(pos, pos+valLength+startPoint)
+	txType, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	switch txType {
+	case types.AccessListTxType:
+		err = DecodeAccessListTxInto(r, rlpBytes, startPoint, tx)
+	case types.DynamicFeeTxType:
+		err = DecodeDynamicFeeTxInto(r, rlpBytes, startPoint, tx)
+	case types.SetCodeTxType:
+		err = DecodeSetCodeTxInto(r, rlpBytes, startPoint, tx)
+	case types.BlobTxType:
+		err = DecodeBlobTxInto(r, rlpBytes, startPoint, tx)
+	default:
+		// up to this point we have read that it is not a supported tx,
+		// so the next thing to do is read the list length and skip the nbytes
+		txListSize, err := r.ReadListSize()
+		if err != nil {
+			return errors.WithTxType(err, int(txType))
+		}
+		err = r.Skip(txListSize)
+		if err != nil {
+			return errors.WithTxType(err, int(txType))
+		}
+		return errors.ErrTxTypeNotSupported
+	}
+	if err != nil {
+		return errors.WithTxType(err, int(txType))
+	}
+	// the tx payload must take exactly the bytes of the rlp string that wraps it
+	if end := pos + uint64(len(rlpBytes)); r.Pos() != end {
+		err = errors.ErrUnexpectedLength.WithMessagef("tx payload ends at %d instead of %d", r.Pos(), end)
+		return errors.WithTxType(errors.AtOffset(err, pos), int(txType))
+	}
+	return nil
+}
+
 // DecodeSetCodeAuthorization parses an RLP-encoded payload into a SetCodeAuthorization struct.
 // It reads and decodes data such as chain ID, address, nonce, and signature values.
 // Returns the decoded SetCodeAuthorization on success, or an error if decoding fails.
@@ -103,50 +181,107 @@ func DecodeSetCodeAuthorization(r *reader.RlpReader) (setCodeAuthorization types
 	}
 	cPos := r.Pos()
 	for r.Pos()-cPos < codeAuthSize {
-		chainId, err := r.DecodeNextValue()
+		chainId, err := decodeUint256(r)
 		if err != nil {
-			return setCodeAuthorization, err
+			return setCodeAuthorization, errors.WithField(err, "chainId")
 		}
 		address, err := r.DecodeNextValue()
 		if err != nil {
-			return setCodeAuthorization, err
+			return setCodeAuthorization, errors.WithField(err, "address")
 		}
-		nonce, err := r.DecodeNextValue()
+		nonce, err := r.DecodeUint64()
 		if err != nil {
-			return setCodeAuthorization, err
+			return setCodeAuthorization, errors.WithField(err, "nonce")
 		}
-		v, err := r.DecodeNextValue()
+		v, err := r.DecodeNextUint()
 		if err != nil {
-			return setCodeAuthorization, err
+			return setCodeAuthorization, errors.WithField(err, "v")
 		}
-		rVal, err := r.DecodeNextValue()
+		rVal, err := decodeUint256(r)
 		if err != nil {
-			return setCodeAuthorization, err
+			return setCodeAuthorization, errors.WithField(err, "r")
 		}
-		s, err := r.DecodeNextValue()
+		s, err := decodeUint256(r)
 		if err != nil {
-			return setCodeAuthorization, err
+			return setCodeAuthorization, errors.WithField(err, "s")
 		}
-		setCodeAuthorization.ChainID = *uint256.MustFromBig(new(big.Int).SetBytes(chainId))
+		setCodeAuthorization.ChainID = chainId
 		setCodeAuthorization.Address = common.BytesToAddress(address)
-		setCodeAuthorization.Nonce = reader.BytesToUint64(nonce)
+		setCodeAuthorization.Nonce = nonce
 		if len(v) == 0 {
 			setCodeAuthorization.V = 0
 		} else {
 			setCodeAuthorization.V = v[len(v)-1]
 		}
 
-		setCodeAuthorization.R = *uint256.MustFromBig(new(big.Int).SetBytes(rVal))
-		setCodeAuthorization.S = *uint256.MustFromBig(new(big.Int).SetBytes(s))
+		setCodeAuthorization.R = rVal
+		setCodeAuthorization.S = s
 	}
 	return setCodeAuthorization, err
 }
 
+// decodeTo decodes the recipient of a tx, which is empty for contract creations or an address
+func decodeTo(r *reader.RlpReader) ([]byte, error) {
+	start := r.Pos()
+	to, err := r.DecodeNextValue()
+	if err != nil {
+		return nil, err
+	}
+	if len(to) != 0 && len(to) != common.AddressLength {
+		err = errors.ErrUnexpectedLength.WithMessagef("to length %d", len(to))
+		return nil, errors.AtOffset(err, start)
+	}
+	return to, nil
+}
+
+// canCreateContracts checks whether the txs of the type can have an empty to, blob and set code txs cannot
+func canCreateContracts(txType uint8) bool {
+	return txType != types.BlobTxType && txType != types.SetCodeTxType
+}
+
+// errEmptyTo is the error of an empty to at start in a tx whose type cannot create contracts
+func errEmptyTo(start uint64) error {
+	return errors.AtOffset(errors.ErrUnexpectedLength.WithMessage("empty to in a tx that cannot create contracts"), start)
+}
+
+// decodeRecipient decodes the to of the tx types that cannot create contracts, which must be an address
+func decodeRecipient(r *reader.RlpReader) ([]byte, error) {
+	start := r.Pos()
+	to, err := decodeTo(r)
+	if err == nil && len(to) == 0 {
+		return nil, errEmptyTo(start)
+	}
+	return to, err
+}
+
+// decodeUint256 decodes the next value as an unsigned integer that must fit in 256 bits
+func decodeUint256(r *reader.RlpReader) (v uint256.Int, err error) {
+	start := r.Pos()
+	b, err := r.DecodeNextUint()
+	if err != nil {
+		return v, err
+	}
+	if len(b) > 32 {
+		err = errors.ErrUnexpectedLength.WithMessagef("integer of %d bytes does not fit in 256 bits", len(b))
+		return v, errors.AtOffset(err, start)
+	}
+	v.SetBytes(b)
+	return v, nil
+}
+
 // DecodeDecodeSetCodeAuthorizationList parses an RLP-encoded payload into a SetCodeAuthorization struct.
 // It reads and decodes data such as chain ID, address, nonce, and signature values.
 // Returns the decoded SetCodeAuthorization on success, or an error if decoding fails.
 func DecodeDecodeSetCodeAuthorizationList(r *reader.RlpReader) (list []types.SetCodeAuthorization, err error) {
-	list = make([]types.SetCodeAuthorization, 0)
+	return decodeSetCodeAuthorizationListInto(r, nil)
+}
+
+// decodeSetCodeAuthorizationListInto is the same as DecodeDecodeSetCodeAuthorizationList but reusing the array of dst
+func decodeSetCodeAuthorizationListInto(r *reader.RlpReader, dst []types.SetCodeAuthorization) (list []types.SetCodeAuthorization, err error) {
+	list = dst[:0]
+	if list == nil {
+		list = make([]types.SetCodeAuthorization, 0)
+	}
 	listSize, err := r.ReadListSize()
 	if err != nil {
 		return list, err
@@ -155,7 +290,7 @@ func DecodeDecodeSetCodeAuthorizationList(r *reader.RlpReader) (list []types.Set
 	for r.Pos()-cPos < listSize {
 		setCodeAuthroization, err := DecodeSetCodeAuthorization(r)
 		if err != nil {
-			return list, err
+			return list, errors.WithField(err, fmt.Sprintf("[%d]", len(list)))
 		}
 		list = append(list, setCodeAuthroization)
 	}
@@ -165,52 +300,81 @@ func DecodeDecodeSetCodeAuthorizationList(r *reader.RlpReader) (list []types.Set
 // DecodeAccessTuple decodes an RLP-encoded access tuple from the provided RlpReader.
 // It returns the decoded access tuple and any error encountered during parsing.
 func DecodeAccessTuple(r *reader.RlpReader) (accessTuple types.AccessTuple, err error) {
+	err = decodeAccessTupleInto(r, &accessTuple)
+	return accessTuple, err
+}
+
+// decodeAccessTupleInto is the same as DecodeAccessTuple but reusing the storage keys array of accessTuple
+func decodeAccessTupleInto(r *reader.RlpReader, accessTuple *types.AccessTuple) error {
+	accessTuple.StorageKeys = accessTuple.StorageKeys[:0]
 	accessTupleSize, err := r.ReadListSize()
 	if err != nil {
-		return accessTuple, err
+		return err
 	}
 	cPos := r.Pos()
 	for r.Pos()-cPos < accessTupleSize {
 		address, err := r.DecodeNextValue()
 		if err != nil {
-			return accessTuple, err
+			return errors.WithField(err, "address")
 		}
 		accessTuple.Address = common.BytesToAddress(address)
 		storageKeysSize, err := r.ReadListSize()
 		if err != nil {
-			return accessTuple, err
+			return errors.WithField(err, "storageKeys")
 		}
 		cStorageKeysPos := r.Pos()
 		for r.Pos()-cStorageKeysPos < storageKeysSize {
 			storageKey, err := r.DecodeNextValue()
 			if err != nil {
-				return accessTuple, err
+				return errors.WithField(err, fmt.Sprintf("storageKeys[%d]", len(accessTuple.StorageKeys)))
 			}
 			accessTuple.StorageKeys = append(accessTuple.StorageKeys, common.BytesToHash(storageKey))
 		}
 	}
-	return accessTuple, err
+	return err
 }
 
 func DecodeAccessList(r *reader.RlpReader) (accessList types.AccessList, err error) {
-	accessList = make(types.AccessList, 0)
+	return decodeAccessListInto(r, nil)
+}
+
+// decodeAccessListInto is the same as DecodeAccessList but reusing the array of dst and the storage keys of its tuples
+func decodeAccessListInto(r *reader.RlpReader, dst types.AccessList) (accessList types.AccessList, err error) {
+	accessList = dst[:0]
+	if accessList == nil {
+		accessList = make(types.AccessList, 0)
+	}
 	accessListSize, err := r.ReadListSize()
 	if err != nil {
 		return accessList, err
 	}
 	cPos := r.Pos()
 	for r.Pos()-cPos < accessListSize {
-		accessTuple, err := DecodeAccessTuple(r)
-		if err != nil {
-			return accessList, err
+		// reuse the tuple left in the array by the previous decode, if any
+		if len(accessList) < cap(accessList) {
+			accessList = accessList[:len(accessList)+1]
+		} else {
+			accessList = append(accessList, types.AccessTuple{})
+		}
+		if err = decodeAccessTupleInto(r, &accessList[len(accessList)-1]); err != nil {
+			return accessList[:len(accessList)-1], errors.WithField(err, fmt.Sprintf("[%d]", len(accessList)-1))
 		}
-		accessList = append(accessList, accessTuple)
 	}
 	return accessList, err
 }
 
 // DecodeLegacyTx decodes a legacy transaction from the provided RLP-encoded byte array and returns a CustomTx instance.
 func DecodeLegacyTx(tx *reader.RlpReader) (*CustomTx, error) {
+	dst := new(CustomTx)
+	if err := DecodeLegacyTxInto(tx, dst); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// DecodeLegacyTxInto is the same as DecodeLegacyTx but decoding into dst, which is reset first. See DecodeTxInto
+func DecodeLegacyTxInto(tx *reader.RlpReader, dst *CustomTx) error {
+	dst.Reset()
 	// store where does the hashing data for signed hash starts
 	// by legacyTx are at 0
 	// store where does the
@@ -219,7 +383,10 @@ func DecodeLegacyTx(tx *reader.RlpReader) (*CustomTx, error) {
 	// check for slice length
 	bytesLength, err := tx.ReadListSize()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if !tx.EnoughBytes(bytesLength) {
+		return errors.AtOffset(io.EOF, cPos)
 	}
 	// store where does the txData starts
 	startTxDataPointer := tx.Pos() - cPos
@@ -228,335 +395,591 @@ func DecodeLegacyTx(tx *reader.RlpReader) (*CustomTx, error) {
 	rlpBytesLength := len(rlpBytes)
 	rlpBytesTxInfo := rlpBytesLength - int(startTxDataPointer)
 
-	nonce, err := tx.DecodeNextValue()
+	nonce, err := tx.DecodeUint64()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "nonce")
 	}
-	gasPrice, err := tx.DecodeNextValue()
+	gasPrice, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "gasPrice")
 	}
-	gas, err := tx.DecodeNextValue()
+	gas, err := tx.DecodeUint64()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "gas")
 	}
-	toBytes, err := tx.DecodeNextValue()
+	toBytes, err := decodeTo(tx)
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "to")
 	}
 	var to *common.Address
 	if len(toBytes) > 0 {
-		to = new(common.Address)
+		to = dst.newAddress()
 		to.SetBytes(toBytes)
 	}
-	value, err := tx.DecodeNextValue()
+	value, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "value")
 	}
 	data, err := tx.DecodeNextValue()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "data")
 	}
 	startTxSignature := tx.Pos() - cPos
-	v, err := tx.DecodeNextValue()
-	if err != nil {
-		return nil, err
-	}
-	r, err := tx.DecodeNextValue()
-	if err != nil {
-		return nil, err
-	}
-	s, err := tx.DecodeNextValue()
-	if err != nil {
-		return nil, err
-	}
-	return &CustomTx{
-		TxType:               types.LegacyTxType,
-		SignedRlpBytes:       rlpBytes,
-		Nonce:                reader.BytesToUint64(nonce),
-		GasPrice:             new(big.Int).SetBytes(gasPrice),
-		Gas:                  reader.BytesToUint64(gas),
-		To:                   to,
-		Value:                new(big.Int).SetBytes(value),
-		Data:                 data,
-		V:                    new(big.Int).SetBytes(v),
-		R:                    new(big.Int).SetBytes(r),
-		S:                    new(big.Int).SetBytes(s),
-		startTx:              0,
-		startTxDataPointer:   int(startTxDataPointer),
-		startTxSignature:     int(startTxSignature),
-		rlpSignedBytesLength: len(rlpBytes),
-		rlpSignedBytesTxInfo: rlpBytesTxInfo,
-	}, nil
+	v, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "v")
+	}
+	r, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "r")
+	}
+	s, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "s")
+	}
+	// the fields must take exactly the bytes of the tx list
+	if end := cPos + uint64(rlpBytesLength); tx.Pos() != end {
+		err = errors.ErrUnexpectedLength.WithMessagef("tx list ends at %d instead of %d", tx.Pos(), end)
+		return errors.AtOffset(err, cPos)
+	}
+	dst.TxType = types.LegacyTxType
+	dst.SignedRlpBytes = rlpBytes
+	dst.Nonce = nonce
+	dst.GasPrice = dst.newBigInt(gasPrice)
+	dst.Gas = gas
+	dst.To = to
+	dst.Value = dst.newBigInt(value)
+	dst.Data = data
+	dst.V = dst.newBigInt(v)
+	dst.R = dst.newBigInt(r)
+	dst.S = dst.newBigInt(s)
+	dst.startTx = 0
+	dst.startTxDataPointer = int(startTxDataPointer)
+	dst.startTxSignature = int(startTxSignature)
+	dst.rlpSignedBytesLength = len(rlpBytes)
+	dst.rlpSignedBytesTxInfo = rlpBytesTxInfo
+	return nil
 }
 
 // DecodeAccessListTx decodes an access list transaction from RLP encoded bytes using the provided RlpReader.
 // rlpBytes fields provides all the bytes of the rlp of the tx in the wire.
 // starPoint indicates where the tx info starts in the rlpBytes slice. Needed to calculate the hash
 func DecodeAccessListTx(tx *reader.RlpReader, rlpBytes []byte, startPoint uint64) (*CustomTx, error) {
+	dst := new(CustomTx)
+	if err := DecodeAccessListTxInto(tx, rlpBytes, startPoint, dst); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// DecodeAccessListTxInto is the same as DecodeAccessListTx but decoding into dst, which is reset first. See DecodeTxInto
+func DecodeAccessListTxInto(tx *reader.RlpReader, rlpBytes []byte, startPoint uint64, dst *CustomTx) error {
+	dst.Reset()
 	// start point will always be txvalsize info - txType, where the position of txType is startPoint.
 	cPos := tx.Pos() - startPoint - 1 // after the startpoint we read one byte, thats why the - 1
 	_, err := tx.ReadListSize()
 	if err != nil {
-		return nil, err
+		return err
 	}
 	startTxDataPointer := tx.Pos() - cPos
 	rlpBytesLength := len(rlpBytes)
-	chainId, err := tx.DecodeNextValue()
+	chainId, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "chainId")
 	}
-	nonce, err := tx.DecodeNextValue()
+	nonce, err := tx.DecodeUint64()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "nonce")
 	}
-	gasPrice, err := tx.DecodeNextValue()
+	gasPrice, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "gasPrice")
 	}
-	gas, err := tx.DecodeNextValue()
+	gas, err := tx.DecodeUint64()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "gas")
 	}
-	toBytes, err := tx.DecodeNextValue()
+	toBytes, err := decodeTo(tx)
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "to")
 	}
 	var to *common.Address
 	if len(toBytes) > 0 {
-		to = new(common.Address)
+		to = dst.newAddress()
 		to.SetBytes(toBytes)
 	}
-	value, err := tx.DecodeNextValue()
+	value, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "value")
 	}
 	data, err := tx.DecodeNextValue()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "data")
 	}
 	var accessList types.AccessList
-	accessList, err = DecodeAccessList(tx)
+	accessList, err = decodeAccessListInto(tx, dst.spare.accessList)
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "accessList")
 	}
 	startTxSignature := tx.Pos() - cPos
-	v, err := tx.DecodeNextValue()
-	if err != nil {
-		return nil, err
-	}
-	r, err := tx.DecodeNextValue()
-	if err != nil {
-		return nil, err
-	}
-	s, err := tx.DecodeNextValue()
-	if err != nil {
-		return nil, err
-	}
-	return &CustomTx{
-		TxType:               types.AccessListTxType,
-		SignedRlpBytes:       rlpBytes,
-		ChainID:              new(big.Int).SetBytes(chainId),
-		Nonce:                reader.BytesToUint64(nonce),
-		GasPrice:             new(big.Int).SetBytes(gasPrice),
-		Gas:                  reader.BytesToUint64(gas),
-		To:                   to,
-		Value:                new(big.Int).SetBytes(value),
-		Data:                 data,
-		V:                    new(big.Int).SetBytes(v),
-		R:                    new(big.Int).SetBytes(r),
-		S:                    new(big.Int).SetBytes(s),
-		AccessList:           accessList,
-		rlpSignedBytesLength: rlpBytesLength,
-		rlpSignedBytesTxInfo: rlpBytesLength - int(startTxDataPointer),
-		startTx:              int(startPoint),
-		startTxDataPointer:   int(startTxDataPointer),
-		startTxSignature:     int(startTxSignature),
-	}, nil
+	v, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "v")
+	}
+	r, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "r")
+	}
+	s, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "s")
+	}
+	dst.TxType = types.AccessListTxType
+	dst.SignedRlpBytes = rlpBytes
+	dst.ChainID = dst.newBigInt(chainId)
+	dst.Nonce = nonce
+	dst.GasPrice = dst.newBigInt(gasPrice)
+	dst.Gas = gas
+	dst.To = to
+	dst.Value = dst.newBigInt(value)
+	dst.Data = data
+	dst.V = dst.newBigInt(v)
+	dst.R = dst.newBigInt(r)
+	dst.S = dst.newBigInt(s)
+	dst.AccessList = accessList
+	dst.rlpSignedBytesLength = rlpBytesLength
+	dst.rlpSignedBytesTxInfo = rlpBytesLength - int(startTxDataPointer)
+	dst.startTx = int(startPoint)
+	dst.startTxDataPointer = int(startTxDataPointer)
+	dst.startTxSignature = int(startTxSignature)
+	return nil
 }
 
 // DecodeDynamicFeeTx decodes a dynamic fee transaction from RLP encoded bytes using the provided RlpReader.
 // rlpBytes fields provides all the bytes of the rlp of the tx in the wire.
 // starPoint indicates where the tx info starts in the rlpBytes slice. Needed to calculate the hash
 func DecodeDynamicFeeTx(tx *reader.RlpReader, rlpBytes []byte, startPoint uint64) (*CustomTx, error) {
+	dst := new(CustomTx)
+	if err := DecodeDynamicFeeTxInto(tx, rlpBytes, startPoint, dst); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// DecodeDynamicFeeTxInto is the same as DecodeDynamicFeeTx but decoding into dst, which is reset first. See DecodeTxInto
+func DecodeDynamicFeeTxInto(tx *reader.RlpReader, rlpBytes []byte, startPoint uint64, dst *CustomTx) error {
+	dst.Reset()
 	// start point will always be txvalsize info - txType, where the position of txType is startPoint.
 	cPos := tx.Pos() - startPoint - 1 // after the startpoint we read one byte, thats why the - 1
 	_, err := tx.ReadListSize()
 	if err != nil {
-		return nil, err
+		return err
 	}
 	startTxDataPointer := tx.Pos() - cPos
 	rlpBytesLength := len(rlpBytes)
-	chainId, err := tx.DecodeNextValue()
+	chainId, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "chainId")
 	}
-	nonce, err := tx.DecodeNextValue()
+	nonce, err := tx.DecodeUint64()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "nonce")
 	}
-	gasTipCap, err := tx.DecodeNextValue()
+	gasTipCap, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "gasTipCap")
 	}
-	gasFeeCap, err := tx.DecodeNextValue()
+	gasFeeCap, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "gasFeeCap")
 	}
-	gas, err := tx.DecodeNextValue()
+	gas, err := tx.DecodeUint64()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "gas")
 	}
-	toBytes, err := tx.DecodeNextValue()
+	toBytes, err := decodeTo(tx)
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "to")
 	}
 	var to *common.Address
 	if len(toBytes) > 0 {
-		to = new(common.Address)
+		to = dst.newAddress()
 		to.SetBytes(toBytes)
 	}
-	value, err := tx.DecodeNextValue()
+	value, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "value")
 	}
 	data, err := tx.DecodeNextValue()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "data")
 	}
 	var accessList types.AccessList
-	accessList, err = DecodeAccessList(tx)
+	accessList, err = decodeAccessListInto(tx, dst.spare.accessList)
 	if err != nil {
+		return errors.WithField(err, "accessList")
+	}
+	startTxSignature := tx.Pos() - cPos
+	v, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "v")
+	}
+	r, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "r")
+	}
+	s, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "s")
+	}
+	dst.TxType = types.DynamicFeeTxType
+	dst.SignedRlpBytes = rlpBytes
+	dst.ChainID = dst.newBigInt(chainId)
+	dst.Nonce = nonce
+	dst.GasTipCap = dst.newBigInt(gasTipCap)
+	dst.GasFeeCap = dst.newBigInt(gasFeeCap)
+	dst.Gas = gas
+	dst.To = to
+	dst.Value = dst.newBigInt(value)
+	dst.Data = data
+	dst.V = dst.newBigInt(v)
+	dst.R = dst.newBigInt(r)
+	dst.S = dst.newBigInt(s)
+	dst.AccessList = accessList
+	dst.rlpSignedBytesLength = rlpBytesLength
+	dst.rlpSignedBytesTxInfo = rlpBytesLength - int(startTxDataPointer)
+	dst.startTx = int(startPoint)
+	dst.startTxDataPointer = int(startTxDataPointer)
+	dst.startTxSignature = int(startTxSignature)
+	return nil
+}
+
+// DecodeSetCodeTx decodes a set code transaction from RLP encoded bytes using the provided RlpReader.
+// rlpBytes fields provides all the bytes of the rlp of the tx in the wire.
+// starPoint indicates where the tx info starts in the rlpBytes slice. Needed to calculate the hash
+func DecodeSetCodeTx(tx *reader.RlpReader, rlpBytes []byte, startPoint uint64) (*CustomTx, error) {
+	dst := new(CustomTx)
+	if err := DecodeSetCodeTxInto(tx, rlpBytes, startPoint, dst); err != nil {
 		return nil, err
 	}
+	return dst, nil
+}
+
+// DecodeSetCodeTxInto is the same as DecodeSetCodeTx but decoding into dst, which is reset first. See DecodeTxInto
+func DecodeSetCodeTxInto(tx *reader.RlpReader, rlpBytes []byte, startPoint uint64, dst *CustomTx) error {
+	dst.Reset()
+	// start point will always be txvalsize info - txType, where the position of txType is startPoint.
+	cPos := tx.Pos() - startPoint - 1 // after the startpoint we read one byte, thats why the - 1
+	_, err := tx.ReadListSize()
+	if err != nil {
+		return err
+	}
+	startTxDataPointer := tx.Pos() - cPos
+	rlpBytesLength := len(rlpBytes)
+	chainId, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "chainId")
+	}
+	nonce, err := tx.DecodeUint64()
+	if err != nil {
+		return errors.WithField(err, "nonce")
+	}
+	gasTipCap, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "gasTipCap")
+	}
+	gasFeeCap, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "gasFeeCap")
+	}
+	gas, err := tx.DecodeUint64()
+	if err != nil {
+		return errors.WithField(err, "gas")
+	}
+	toBytes, err := decodeRecipient(tx)
+	if err != nil {
+		return errors.WithField(err, "to")
+	}
+	to := dst.newAddress()
+	to.SetBytes(toBytes)
+	value, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "value")
+	}
+	data, err := tx.DecodeNextValue()
+	if err != nil {
+		return errors.WithField(err, "data")
+	}
+	var accessList types.AccessList
+	accessList, err = decodeAccessListInto(tx, dst.spare.accessList)
+	if err != nil {
+		return errors.WithField(err, "accessList")
+	}
+	var authList []types.SetCodeAuthorization
+	authList, err = decodeSetCodeAuthorizationListInto(tx, dst.spare.authList)
+	if err != nil {
+		return errors.WithField(err, "authList")
+	}
 	startTxSignature := tx.Pos() - cPos
-	v, err := tx.DecodeNextValue()
+	v, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "v")
+	}
+	r, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "r")
+	}
+	s, err := tx.DecodeNextUint()
+	if err != nil {
+		return errors.WithField(err, "s")
+	}
+	dst.TxType = types.SetCodeTxType
+	dst.SignedRlpBytes = rlpBytes
+	dst.ChainID = dst.newBigInt(chainId)
+	dst.Nonce = nonce
+	dst.GasTipCap = dst.newBigInt(gasTipCap)
+	dst.GasFeeCap = dst.newBigInt(gasFeeCap)
+	dst.Gas = gas
+	dst.To = to
+	dst.Value = dst.newBigInt(value)
+	dst.Data = data
+	dst.V = dst.newBigInt(v)
+	dst.R = dst.newBigInt(r)
+	dst.S = dst.newBigInt(s)
+	dst.AccessList = accessList
+	dst.AuthList = authList
+	dst.rlpSignedBytesLength = rlpBytesLength
+	dst.rlpSignedBytesTxInfo = rlpBytesLength - int(startTxDataPointer)
+	dst.startTx = int(startPoint)
+	dst.startTxDataPointer = int(startTxDataPointer)
+	dst.startTxSignature = int(startTxSignature)
+	return nil
+}
+
+// DecodeBlobHashes decodes the RLP-encoded list of blob versioned hashes from the provided RlpReader.
+func DecodeBlobHashes(r *reader.RlpReader) (blobHashes []common.Hash, err error) {
+	return decodeBlobHashesInto(r, nil)
+}
+
+// decodeBlobHashesInto is the same as DecodeBlobHashes but reusing the array of dst
+func decodeBlobHashesInto(r *reader.RlpReader, dst []common.Hash) (blobHashes []common.Hash, err error) {
+	blobHashes = dst[:0]
+	if blobHashes == nil {
+		blobHashes = make([]common.Hash, 0)
+	}
+	listSize, err := r.ReadListSize()
 	if err != nil {
-		return nil, err
+		return blobHashes, err
+	}
+	cPos := r.Pos()
+	for r.Pos()-cPos < listSize {
+		start := r.Pos()
+		h, err := r.DecodeNextValue()
+		if err == nil && len(h) != common.HashLength {
+			err = errors.AtOffset(errors.ErrUnexpectedLength.WithMessagef("blob hash of %d bytes", len(h)), start)
+		}
+		if err != nil {
+			return blobHashes, errors.WithField(err, fmt.Sprintf("[%d]", len(blobHashes)))
+		}
+		blobHashes = append(blobHashes, common.BytesToHash(h))
 	}
-	r, err := tx.DecodeNextValue()
+	return blobHashes, err
+}
+
+// DecodeBlobTxSidecar decodes the blobs, commitments and proofs lists that follow the tx payload in the network
+// form of a blob transaction.
+func DecodeBlobTxSidecar(r *reader.RlpReader) (*types.BlobTxSidecar, error) {
+	sidecar := new(types.BlobTxSidecar)
+	blobsSize, err := r.ReadListSize()
 	if err != nil {
-		return nil, err
+		return nil, errors.WithField(err, "blobs")
+	}
+	cPos := r.Pos()
+	for r.Pos()-cPos < blobsSize {
+		start := r.Pos()
+		b, err := r.DecodeNextValue()
+		if err != nil {
+			return nil, errors.WithField(err, fmt.Sprintf("blobs[%d]", len(sidecar.Blobs)))
+		}
+		if len(b) != len(kzg4844.Blob{}) {
+			err = errors.ErrUnexpectedLength.WithMessagef("blob length %d", len(b))
+			return nil, errors.WithField(errors.AtOffset(err, start), fmt.Sprintf("blobs[%d]", len(sidecar.Blobs)))
+		}
+		var blob kzg4844.Blob
+		copy(blob[:], b)
+		sidecar.Blobs = append(sidecar.Blobs, blob)
 	}
-	s, err := tx.DecodeNextValue()
+	commitmentsSize, err := r.ReadListSize()
 	if err != nil {
-		return nil, err
+		return nil, errors.WithField(err, "commitments")
 	}
-	return &CustomTx{
-		TxType:               types.DynamicFeeTxType,
-		SignedRlpBytes:       rlpBytes,
-		ChainID:              new(big.Int).SetBytes(chainId),
-		Nonce:                reader.BytesToUint64(nonce),
-		GasTipCap:            new(big.Int).SetBytes(gasTipCap),
-		GasFeeCap:            new(big.Int).SetBytes(gasFeeCap),
-		Gas:                  reader.BytesToUint64(gas),
-		To:                   to,
-		Value:                new(big.Int).SetBytes(value),
-		Data:                 data,
-		V:                    new(big.Int).SetBytes(v),
-		R:                    new(big.Int).SetBytes(r),
-		S:                    new(big.Int).SetBytes(s),
-		AccessList:           accessList,
-		rlpSignedBytesLength: rlpBytesLength,
-		rlpSignedBytesTxInfo: rlpBytesLength - int(startTxDataPointer),
-		startTx:              int(startPoint),
-		startTxDataPointer:   int(startTxDataPointer),
-		startTxSignature:     int(startTxSignature),
-	}, nil
-}
-
-// DecodeDynamicFeeTx decodes an dynamic fee transaction from RLP encoded bytes using the provided RlpReader.
+	cPos = r.Pos()
+	for r.Pos()-cPos < commitmentsSize {
+		start := r.Pos()
+		c, err := r.DecodeNextValue()
+		if err != nil {
+			return nil, errors.WithField(err, fmt.Sprintf("commitments[%d]", len(sidecar.Commitments)))
+		}
+		if len(c) != len(kzg4844.Commitment{}) {
+			err = errors.ErrUnexpectedLength.WithMessagef("commitment length %d", len(c))
+			return nil, errors.WithField(errors.AtOffset(err, start), fmt.Sprintf("commitments[%d]", len(sidecar.Commitments)))
+		}
+		var commitment kzg4844.Commitment
+		copy(commitment[:], c)
+		sidecar.Commitments = append(sidecar.Commitments, commitment)
+	}
+	proofsSize, err := r.ReadListSize()
+	if err != nil {
+		return nil, errors.WithField(err, "proofs")
+	}
+	cPos = r.Pos()
+	for r.Pos()-cPos < proofsSize {
+		start := r.Pos()
+		p, err := r.DecodeNextValue()
+		if err != nil {
+			return nil, errors.WithField(err, fmt.Sprintf("proofs[%d]", len(sidecar.Proofs)))
+		}
+		if len(p) != len(kzg4844.Proof{}) {
+			err = errors.ErrUnexpectedLength.WithMessagef("proof length %d", len(p))
+			return nil, errors.WithField(errors.AtOffset(err, start), fmt.Sprintf("proofs[%d]", len(sidecar.Proofs)))
+		}
+		var proof kzg4844.Proof
+		copy(proof[:], p)
+		sidecar.Proofs = append(sidecar.Proofs, proof)
+	}
+	return sidecar, nil
+}
+
+// DecodeBlobTx decodes a blob transaction from RLP encoded bytes using the provided RlpReader.
+// Both the canonical form (tx payload only) and the network form (tx payload, blobs, commitments, proofs) are supported.
 // rlpBytes fields provides all the bytes of the rlp of the tx in the wire.
 // starPoint indicates where the tx info starts in the rlpBytes slice. Needed to calculate the hash
-func DecodeSetCodeTx(tx *reader.RlpReader, rlpBytes []byte, startPoint uint64) (*CustomTx, error) {
+func DecodeBlobTx(tx *reader.RlpReader, rlpBytes []byte, startPoint uint64) (*CustomTx, error) {
+	dst := new(CustomTx)
+	if err := DecodeBlobTxInto(tx, rlpBytes, startPoint, dst); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// DecodeBlobTxInto is the same as DecodeBlobTx but decoding into dst, which is reset first. See DecodeTxInto
+func DecodeBlobTxInto(tx *reader.RlpReader, rlpBytes []byte, startPoint uint64, dst *CustomTx) error {
+	dst.Reset()
 	// start point will always be txvalsize info - txType, where the position of txType is startPoint.
 	cPos := tx.Pos() - startPoint - 1 // after the startpoint we read one byte, thats why the - 1
 	_, err := tx.ReadListSize()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	// the network form wraps the tx payload in another list, so if the first element is a list we have the sidecar
+	var startBlobTxPayload, endBlobTxPayload uint64
+	withSidecar := tx.IsNextValAList()
+	if withSidecar {
+		startBlobTxPayload = tx.Pos() - cPos
+		payloadSize, err := tx.ReadListSize()
+		if err != nil {
+			return err
+		}
+		endBlobTxPayload = tx.Pos() - cPos + payloadSize
 	}
 	startTxDataPointer := tx.Pos() - cPos
 	rlpBytesLength := len(rlpBytes)
-	chainId, err := tx.DecodeNextValue()
+	chainId, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "chainId")
 	}
-	nonce, err := tx.DecodeNextValue()
+	nonce, err := tx.DecodeUint64()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "nonce")
 	}
-	gasTipCap, err := tx.DecodeNextValue()
+	gasTipCap, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "gasTipCap")
 	}
-	gasFeeCap, err := tx.DecodeNextValue()
+	gasFeeCap, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "gasFeeCap")
 	}
-	gas, err := tx.DecodeNextValue()
+	gas, err := tx.DecodeUint64()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "gas")
 	}
-	toBytes, err := tx.DecodeNextValue()
+	toBytes, err := decodeRecipient(tx)
 	if err != nil {
-		return nil, err
-	}
-	var to *common.Address
-	if len(toBytes) > 0 {
-		to = new(common.Address)
-		to.SetBytes(toBytes)
+		return errors.WithField(err, "to")
 	}
-	value, err := tx.DecodeNextValue()
+	to := dst.newAddress()
+	to.SetBytes(toBytes)
+	value, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "value")
 	}
 	data, err := tx.DecodeNextValue()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "data")
 	}
 	var accessList types.AccessList
-	accessList, err = DecodeAccessList(tx)
+	accessList, err = decodeAccessListInto(tx, dst.spare.accessList)
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "accessList")
 	}
-	var authList []types.SetCodeAuthorization
-	authList, err = DecodeDecodeSetCodeAuthorizationList(tx)
+	blobFeeCap, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "blobFeeCap")
+	}
+	var blobHashes []common.Hash
+	blobHashes, err = decodeBlobHashesInto(tx, dst.spare.blobHashes)
+	if err != nil {
+		return errors.WithField(err, "blobHashes")
 	}
 	startTxSignature := tx.Pos() - cPos
-	v, err := tx.DecodeNextValue()
+	v, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "v")
 	}
-	r, err := tx.DecodeNextValue()
+	r, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "r")
 	}
-	s, err := tx.DecodeNextValue()
+	s, err := tx.DecodeNextUint()
 	if err != nil {
-		return nil, err
+		return errors.WithField(err, "s")
+	}
+	var sidecar *types.BlobTxSidecar
+	if withSidecar {
+		// the fields must take exactly the bytes of the tx payload list, which is hashed on its own
+		if end := cPos + endBlobTxPayload; tx.Pos() != end {
+			err = errors.ErrUnexpectedLength.WithMessagef("tx payload ends at %d instead of %d", tx.Pos(), end)
+			return errors.AtOffset(err, cPos+startBlobTxPayload)
+		}
+		sidecar, err = DecodeBlobTxSidecar(tx)
+		if err != nil {
+			return errors.WithField(err, "sidecar")
+		}
 	}
-	return &CustomTx{
-		TxType:               types.SetCodeTxType,
-		SignedRlpBytes:       rlpBytes,
-		ChainID:              new(big.Int).SetBytes(chainId),
-		Nonce:                reader.BytesToUint64(nonce),
-		GasTipCap:            new(big.Int).SetBytes(gasTipCap),
-		GasFeeCap:            new(big.Int).SetBytes(gasFeeCap),
-		Gas:                  reader.BytesToUint64(gas),
-		To:                   to,
-		Value:                new(big.Int).SetBytes(value),
-		Data:                 data,
-		V:                    new(big.Int).SetBytes(v),
-		R:                    new(big.Int).SetBytes(r),
-		S:                    new(big.Int).SetBytes(s),
-		AccessList:           accessList,
-		AuthList:             authList,
-		rlpSignedBytesLength: rlpBytesLength,
-		rlpSignedBytesTxInfo: rlpBytesLength - int(startTxDataPointer),
-		startTx:              int(startPoint),
-		startTxDataPointer:   int(startTxDataPointer),
-		startTxSignature:     int(startTxSignature),
-	}, nil
+	dst.TxType = types.BlobTxType
+	dst.SignedRlpBytes = rlpBytes
+	dst.ChainID = dst.newBigInt(chainId)
+	dst.Nonce = nonce
+	dst.GasTipCap = dst.newBigInt(gasTipCap)
+	dst.GasFeeCap = dst.newBigInt(gasFeeCap)
+	dst.Gas = gas
+	dst.To = to
+	dst.Value = dst.newBigInt(value)
+	dst.Data = data
+	dst.V = dst.newBigInt(v)
+	dst.R = dst.newBigInt(r)
+	dst.S = dst.newBigInt(s)
+	dst.AccessList = accessList
+	dst.BlobFeeCap = dst.newBigInt(blobFeeCap)
+	dst.BlobHashes = blobHashes
+	dst.Sidecar = sidecar
+	dst.rlpSignedBytesLength = rlpBytesLength
+	dst.rlpSignedBytesTxInfo = rlpBytesLength - int(startTxDataPointer)
+	dst.startTx = int(startPoint)
+	dst.startTxDataPointer = int(startTxDataPointer)
+	dst.startTxSignature = int(startTxSignature)
+	dst.startBlobTxPayload = int(startBlobTxPayload)
+	dst.endBlobTxPayload = int(endBlobTxPayload)
+	return nil
 }
@@ -5,16 +5,19 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"fmt"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
 	"github.com/1aBcD1234aBcD1/prlp/pool"
 	reader2 "github.com/1aBcD1234aBcD1/prlp/reader"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"math/big"
 	"testing"
 )
@@ -747,3 +750,523 @@ func TestDecodeLegacyTxsRLPAndVals(t *testing.T) {
 //		t.Errorf("Decode did not consume all data, %d bytes remaining", reader.Len())
 //	}
 //}
+
+func newSignedBlobTxForTests(t testing.TB, nonce uint64) *types.Transaction {
+	privateKey, err := getPrivkeyForTests()
+	if err != nil {
+		t.Fatalf("Failed to get private key: %v", err)
+	}
+	chainID := big.NewInt(6969)
+	tx := types.NewTx(&types.BlobTx{
+		ChainID:   uint256.MustFromBig(chainID),
+		Nonce:     nonce,
+		GasTipCap: uint256.NewInt(2000000000),
+		GasFeeCap: uint256.NewInt(20000000000),
+		Gas:       21000,
+		To:        common.Address{0xde, 0xad},
+		Value:     uint256.NewInt(1000000000000000000),
+		Data:      []byte{0xaa, 0xbb},
+		AccessList: types.AccessList{
+			{
+				Address:     common.Address{0x01},
+				StorageKeys: []common.Hash{{0x02}},
+			},
+		},
+		BlobFeeCap: uint256.NewInt(30000000000),
+		BlobHashes: []common.Hash{{0x01, 0x01}, {0x01, 0x02}},
+		Sidecar: &types.BlobTxSidecar{
+			Blobs:       []kzg4844.Blob{{0x0a, 0x0b, 0x0c}, {0x0d, 0x0e}},
+			Commitments: []kzg4844.Commitment{{0x01}, {0x02}},
+			Proofs:      []kzg4844.Proof{{0x03}, {0x04}},
+		},
+	})
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), privateKey)
+	if err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	return signedTx
+}
+
+func TestDecodeBlobTxNetworkAndCanonicalForm(t *testing.T) {
+	withSidecar := newSignedBlobTxForTests(t, 1)
+	withoutSidecar := newSignedBlobTxForTests(t, 2).WithoutBlobTxSidecar()
+	txs := types.Transactions{withSidecar, withoutSidecar}
+
+	rlpBytes, err := rlp.EncodeToBytes(eth.PooledTransactionsPacket{
+		RequestId:                  1,
+		PooledTransactionsResponse: eth.PooledTransactionsResponse(txs),
+	})
+	if err != nil {
+		t.Fatalf("Failed to RLP encode transactions: %v", err)
+	}
+
+	r := reader2.NewReader(rlpBytes)
+	customTxs, err := DecodePoolTxsPacket(r)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), r.Len(), "not all data consumed")
+	assert.Len(t, customTxs, len(txs))
+
+	for i, want := range txs {
+		got := customTxs[i]
+		assert.Equal(t, want.Hash(), got.Hash(), "pos %d hash mismatch", i)
+		compareBlobTx(t, got, want)
+
+		wantRLP, err := rlp.EncodeToBytes(want)
+		assert.NoError(t, err)
+		assert.Equal(t, wantRLP, got.SignedRlpBytes, "pos %d rlp mismatch", i)
+
+		from, err := types.Sender(types.LatestSignerForChainID(want.ChainId()), want)
+		assert.NoError(t, err)
+		gotFrom, err := got.From()
+		assert.NoError(t, err)
+		assert.Equal(t, from, gotFrom, "pos %d from mismatch", i)
+
+		// the unsigned hash must be the same when the tx has to be encoded from its values
+		got.SignedRlpBytes = []byte{}
+		assert.Equal(t, types.LatestSignerForChainID(want.ChainId()).Hash(want), got.CalculateUnsignedHash(), "pos %d unsigned hash mismatch", i)
+	}
+}
+
+func TestDecodeTxsPacketSetCodeTxs(t *testing.T) {
+	privateKey, err := getPrivkeyForTests()
+	if err != nil {
+		t.Fatalf("Failed to get private key: %v", err)
+	}
+	chainID := big.NewInt(6969)
+	signer := types.LatestSignerForChainID(chainID)
+
+	auth, err := types.SignSetCode(privateKey, types.SetCodeAuthorization{
+		ChainID: *uint256.MustFromBig(chainID),
+		Address: common.Address{0xde, 0xad, 0xde, 0xad},
+		Nonce:   123131,
+	})
+	if err != nil {
+		t.Fatalf("Failed to sign authorization: %v", err)
+	}
+
+	txsData := []types.TxData{
+		&types.SetCodeTx{
+			ChainID:   uint256.MustFromBig(chainID),
+			Nonce:     1,
+			GasTipCap: uint256.NewInt(1000000000),
+			GasFeeCap: uint256.NewInt(2000000000),
+			Gas:       100000,
+			To:        common.Address{0x01},
+			Value:     uint256.NewInt(0),
+			AuthList:  []types.SetCodeAuthorization{auth},
+		},
+		&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     2,
+			GasTipCap: big.NewInt(1000000000),
+			GasFeeCap: big.NewInt(2000000000),
+			Gas:       21000,
+			To:        &common.Address{0x02},
+			Value:     big.NewInt(1),
+		},
+		&types.SetCodeTx{
+			ChainID:   uint256.MustFromBig(chainID),
+			Nonce:     3,
+			GasTipCap: uint256.NewInt(1000000000),
+			GasFeeCap: uint256.NewInt(2000000000),
+			Gas:       100000,
+			To:        common.Address{0x03},
+			Value:     uint256.NewInt(1000),
+			Data:      []byte{0xaa, 0xbb, 0xcc},
+			AuthList:  []types.SetCodeAuthorization{auth, auth},
+		},
+	}
+
+	var txs types.Transactions
+	for _, txData := range txsData {
+		signedTx, err := types.SignNewTx(privateKey, signer, txData)
+		if err != nil {
+			t.Fatalf("Failed to sign transaction: %v", err)
+		}
+		txs = append(txs, signedTx)
+	}
+
+	rlpBytes, err := rlp.EncodeToBytes(txs)
+	if err != nil {
+		t.Fatalf("Failed to RLP encode transactions: %v", err)
+	}
+
+	r := reader2.NewReader(rlpBytes)
+	customTxs, err := DecodeTxsPacket(r)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), r.Len(), "not all data consumed")
+	assert.Len(t, customTxs, len(txs))
+
+	for i, want := range txs {
+		got := customTxs[i]
+		assert.Equal(t, want.Type(), got.TxType, "pos %d type mismatch", i)
+		assert.Equal(t, want.Hash(), got.Hash(), "pos %d hash mismatch", i)
+		if want.Type() == types.SetCodeTxType {
+			compareSetCodeTx(t, got, want)
+		}
+
+		from, err := types.Sender(signer, want)
+		assert.NoError(t, err)
+		gotFrom, err := got.From()
+		assert.NoError(t, err)
+		assert.Equal(t, from, gotFrom, "pos %d from mismatch", i)
+	}
+}
+
+// rlpListForTests wraps the already encoded items into a canonical rlp list
+func rlpListForTests(items ...[]byte) []byte {
+	raw := make([]rlp.RawValue, len(items))
+	for i, item := range items {
+		raw[i] = item
+	}
+	b, _ := rlp.EncodeToBytes(raw)
+	return b
+}
+
+func TestDecodeTxStrict(t *testing.T) {
+	to := append([]byte{0x94}, common.HexToAddress("0x2f2a2543b76a4166549f7aab2e75bef0aefc5b0f").Bytes()...)
+	sig := append([]byte{0xa0}, bytes.Repeat([]byte{0x11}, 32)...)
+	legacyTx := func(nonce, data, value []byte) []byte {
+		return rlpListForTests(nonce, []byte{0x84, 0x3b, 0x9a, 0xca, 0x00}, []byte{0x82, 0x52, 0x08}, to, value, data,
+			[]byte{0x25}, sig, sig)
+	}
+	canonical := legacyTx([]byte{0x05}, []byte{0x80}, []byte{0x80})
+	// the same list but with its size in the long form with a leading zero
+	leadingZeroListSize := append([]byte{0xf9, 0x00, byte(len(canonical) - 2)}, canonical[2:]...)
+
+	tests := []struct {
+		Name      string
+		Rlp       []byte
+		WantError error
+		Malformed bool
+	}{
+		{
+			Name: "Test canonical tx",
+			Rlp:  canonical,
+		},
+		{
+			Name:      "Test integer with leading zeros",
+			Rlp:       legacyTx([]byte{0x82, 0x00, 0x05}, []byte{0x80}, []byte{0x80}),
+			WantError: errors.ErrIntLeadingZero,
+		},
+		{
+			Name:      "Test integer zero encoded as 0x00",
+			Rlp:       legacyTx([]byte{0x05}, []byte{0x80}, []byte{0x00}),
+			WantError: errors.ErrIntLeadingZero,
+		},
+		{
+			Name:      "Test single byte wrapped in 0x81",
+			Rlp:       legacyTx([]byte{0x81, 0x05}, []byte{0x80}, []byte{0x80}),
+			WantError: errors.ErrCannotValueBeASingleByte,
+		},
+		{
+			Name:      "Test long form string size < 56",
+			Rlp:       legacyTx([]byte{0x05}, []byte{0xb8, 0x02, 0xaa, 0xbb}, []byte{0x80}),
+			WantError: errors.ErrNonCanonicalSize,
+		},
+		{
+			Name:      "Test string size with leading zeros",
+			Rlp:       legacyTx([]byte{0x05}, append([]byte{0xb9, 0x00, 0x38}, bytes.Repeat([]byte{0xaa}, 0x38)...), []byte{0x80}),
+			WantError: errors.ErrSizeLeadingZero,
+		},
+		{
+			Name:      "Test list size with leading zeros",
+			Rlp:       leadingZeroListSize,
+			WantError: errors.ErrSizeLeadingZero,
+		},
+		{
+			// would be decoded as nonce 5, keeping the last 8 bytes
+			Name:      "Test nonce longer than 8 bytes",
+			Rlp:       legacyTx([]byte{0x89, 0x01, 0, 0, 0, 0, 0, 0, 0, 0x05}, []byte{0x80}, []byte{0x80}),
+			WantError: errors.ErrUnexpectedLength,
+			Malformed: true,
+		},
+		{
+			Name: "Test to of 3 bytes",
+			Rlp: rlpListForTests([]byte{0x05}, []byte{0x84, 0x3b, 0x9a, 0xca, 0x00}, []byte{0x82, 0x52, 0x08},
+				[]byte{0x83, 0x01, 0x02, 0x03}, []byte{0x80}, []byte{0x80}, []byte{0x25}, sig, sig),
+			WantError: errors.ErrUnexpectedLength,
+			Malformed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// the default reader is lenient with non-canonical encodings, but not with malformed txs
+			_, err := DecodeTx(reader2.NewReader(tt.Rlp))
+			if tt.Malformed {
+				assert.True(t, errors.Is(err, tt.WantError), "expected %v, got %v", tt.WantError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			tx, err := DecodeTx(reader2.NewStrictReader(tt.Rlp))
+			gethErr := rlp.DecodeBytes(tt.Rlp, new(types.Transaction))
+			if tt.WantError != nil {
+				assert.True(t, errors.Is(err, tt.WantError), "expected %v, got %v", tt.WantError, err)
+				assert.Error(t, gethErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, gethErr)
+			assert.Equal(t, tt.Rlp, tx.SignedRlpBytes)
+		})
+	}
+}
+
+func TestDecodeTx_RecipientAndBlobHashes(t *testing.T) {
+	to := append([]byte{0x94}, common.HexToAddress("0x2f2a2543b76a4166549f7aab2e75bef0aefc5b0f").Bytes()...)
+	sig := append([]byte{0xa0}, bytes.Repeat([]byte{0x11}, 32)...)
+	hash := append([]byte{0xa0}, bytes.Repeat([]byte{0x01}, 32)...)
+	typedTx := func(txType byte, fields ...[]byte) []byte {
+		b, _ := rlp.EncodeToBytes(append([]byte{txType}, rlpListForTests(fields...)...))
+		return b
+	}
+	blobTx := func(to, blobHashes []byte) []byte {
+		return typedTx(types.BlobTxType, []byte{0x01}, []byte{0x05}, []byte{0x01}, []byte{0x01},
+			[]byte{0x82, 0x52, 0x08}, to, []byte{0x80}, []byte{0x80}, []byte{0xc0}, []byte{0x01}, blobHashes,
+			[]byte{0x01}, sig, sig)
+	}
+	setCodeTx := func(to []byte) []byte {
+		return typedTx(types.SetCodeTxType, []byte{0x01}, []byte{0x05}, []byte{0x01}, []byte{0x01},
+			[]byte{0x82, 0x52, 0x08}, to, []byte{0x80}, []byte{0x80}, []byte{0xc0}, []byte{0xc0}, []byte{0x01}, sig, sig)
+	}
+
+	tests := []struct {
+		Name      string
+		Rlp       []byte
+		WantField string
+	}{
+		{
+			Name: "Test valid blob tx",
+			Rlp:  blobTx(to, rlpListForTests(hash, hash)),
+		},
+		{
+			Name: "Test valid set code tx",
+			Rlp:  setCodeTx(to),
+		},
+		{
+			Name:      "Test blob tx with an empty to",
+			Rlp:       blobTx([]byte{0x80}, rlpListForTests(hash)),
+			WantField: "to",
+		},
+		{
+			Name:      "Test set code tx with an empty to",
+			Rlp:       setCodeTx([]byte{0x80}),
+			WantField: "to",
+		},
+		{
+			Name:      "Test blob hash of 31 bytes",
+			Rlp:       blobTx(to, rlpListForTests(hash, append([]byte{0x9f}, hash[2:]...))),
+			WantField: "blobHashes[1]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			_, err := DecodeTx(reader2.NewReader(tt.Rlp))
+			_, err256 := DecodeTx256(reader2.NewReader(tt.Rlp))
+			// the lazy tx decodes the blob hashes when they are requested
+			lazy, errLazy := DecodeLazyTx(reader2.NewReader(tt.Rlp))
+			if errLazy == nil {
+				_, errLazy = lazy.BlobHashes()
+			}
+			if tt.WantField == "" {
+				assert.NoError(t, err)
+				assert.NoError(t, err256)
+				assert.NoError(t, errLazy)
+				return
+			}
+			for _, err := range []error{err, err256, errLazy} {
+				assert.True(t, errors.Is(err, errors.ErrUnexpectedLength), "unexpected error %v", err)
+				if pos := errors.GetPosition(err); assert.NotNil(t, pos) {
+					assert.Equal(t, tt.WantField, pos.Field)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeTxsPacketErrorPosition(t *testing.T) {
+	to := append([]byte{0x94}, common.HexToAddress("0x2f2a2543b76a4166549f7aab2e75bef0aefc5b0f").Bytes()...)
+	sig := append([]byte{0xa0}, bytes.Repeat([]byte{0x11}, 32)...)
+	typedTx := func(txType byte, fields ...[]byte) []byte {
+		b, _ := rlp.EncodeToBytes(append([]byte{txType}, rlpListForTests(fields...)...))
+		return b
+	}
+	legacyTx := rlpListForTests([]byte{0x05}, []byte{0x01}, []byte{0x82, 0x52, 0x08}, to, []byte{0x80}, []byte{0x80},
+		[]byte{0x25}, sig, sig)
+	// gasFeeCap with a leading zero byte
+	badGasFeeCap := []byte{0x82, 0x00, 0x01}
+	dynamicFeeTx := typedTx(types.DynamicFeeTxType, []byte{0x38}, []byte{0x01}, []byte{0x01}, badGasFeeCap,
+		[]byte{0x82, 0x52, 0x08}, to, []byte{0x80}, []byte{0x80}, []byte{0xc0}, []byte{0x01}, sig, sig)
+	// second storage key of the first access tuple encoded as a wrapped single byte
+	badStorageKey := []byte{0x81, 0x05}
+	accessList := rlpListForTests(rlpListForTests(to, rlpListForTests(append([]byte{0xa0}, make([]byte, 32)...), badStorageKey)))
+	accessListTx := typedTx(types.AccessListTxType, []byte{0x38}, []byte{0x01}, []byte{0x01}, []byte{0x82, 0x52, 0x08},
+		to, []byte{0x80}, []byte{0x80}, accessList, []byte{0x01}, sig, sig)
+
+	tests := []struct {
+		Name    string
+		Packet  []byte
+		Strict  bool
+		Want    errors.Position
+		WantErr error
+	}{
+		{
+			Name:    "Test non-canonical field",
+			Packet:  rlpListForTests(legacyTx, dynamicFeeTx),
+			Strict:  true,
+			Want:    errors.Position{TxIndex: 1, TxType: types.DynamicFeeTxType, Field: "gasFeeCap"},
+			WantErr: errors.ErrIntLeadingZero,
+		},
+		{
+			Name:    "Test non-canonical nested field",
+			Packet:  rlpListForTests(legacyTx, legacyTx, accessListTx),
+			Strict:  true,
+			Want:    errors.Position{TxIndex: 2, TxType: types.AccessListTxType, Field: "accessList[0].storageKeys[1]"},
+			WantErr: errors.ErrCannotValueBeASingleByte,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			r := reader2.NewReader(tt.Packet)
+			r.SetStrict(tt.Strict)
+			_, err := DecodeTxsPacket(r)
+			assert.True(t, errors.Is(err, tt.WantErr), "unexpected error %v", err)
+			pos := errors.GetPosition(err)
+			if assert.NotNil(t, pos) {
+				assert.Equal(t, tt.Want.TxIndex, pos.TxIndex)
+				assert.Equal(t, tt.Want.TxType, pos.TxType)
+				assert.Equal(t, tt.Want.Field, pos.Field)
+			}
+		})
+	}
+
+	t.Run("Test error message", func(t *testing.T) {
+		packet := rlpListForTests(legacyTx, dynamicFeeTx)
+		_, err := DecodeTxsPacket(reader2.NewStrictReader(packet))
+		offset := bytes.Index(packet, badGasFeeCap)
+		assert.Contains(t, err.Error(), fmt.Sprintf("tx[1] type=2 field=gasFeeCap at offset %d", offset))
+	})
+
+	t.Run("Test truncated packet", func(t *testing.T) {
+		packet := rlpListForTests(legacyTx, dynamicFeeTx)
+		_, err := DecodeTxsPacket(reader2.NewReader(packet[:len(packet)-10]))
+		assert.ErrorIs(t, err, io.EOF)
+		pos := errors.GetPosition(err)
+		if assert.NotNil(t, pos) {
+			assert.Equal(t, 1, pos.TxIndex)
+			// the packet list header takes 2 bytes
+			assert.Equal(t, uint64(2+len(legacyTx)), pos.Offset)
+		}
+	})
+}
+
+func TestDecodeTxsPacketInto(t *testing.T) {
+	txs := append(fuzzSeedTxsForTests(t), newSignedBlobTxForTests(t, 7))
+	reversed := make([]*types.Transaction, len(txs))
+	for i, tx := range txs {
+		reversed[len(txs)-1-i] = tx
+	}
+	var dst []*CustomTx
+	// decoding the txs in the reverse order reuses the values of txs of other types
+	for _, packetTxs := range [][]*types.Transaction{txs, reversed, txs[:2], txs} {
+		packet, err := rlp.EncodeToBytes(packetTxs)
+		assert.NoError(t, err)
+		expected, err := DecodeTxsPacket(reader2.NewReader(packet))
+		assert.NoError(t, err)
+		var gethTxs []*types.Transaction
+		assert.NoError(t, rlp.DecodeBytes(packet, &gethTxs))
+		dst, err = DecodeTxsPacketInto(reader2.NewReader(packet), dst)
+		assert.NoError(t, err)
+		assert.Len(t, dst, len(packetTxs))
+		for i, tx := range dst {
+			compareTxFieldsForTests(t, tx, gethTxs[i])
+			// the values a tx type does not have are not left from the previous tx
+			for j, v := range [][2]interface{}{{expected[i].GasPrice, tx.GasPrice}, {expected[i].ChainID, tx.ChainID},
+				{expected[i].GasTipCap, tx.GasTipCap}, {expected[i].BlobFeeCap, tx.BlobFeeCap},
+				{expected[i].AccessList, tx.AccessList}, {expected[i].BlobHashes, tx.BlobHashes},
+				{expected[i].AuthList, tx.AuthList}, {expected[i].Sidecar, tx.Sidecar}} {
+				assert.Equal(t, assert.ObjectsAreEqual(nil, v[0]), assert.ObjectsAreEqual(nil, v[1]), "value %d of tx %d", j, i)
+			}
+			assert.Equal(t, packetTxs[i].Hash(), tx.Hash())
+			from, err := tx.From()
+			assert.NoError(t, err)
+			expectedFrom, err := expected[i].From()
+			assert.NoError(t, err)
+			assert.Equal(t, expectedFrom, from)
+		}
+	}
+	// the txs of the array are reused
+	assert.Same(t, dst[:cap(dst)][len(txs)-1], dst[len(txs)-1])
+
+	// once the txs hold their values, draining packets into them does not allocate per tx
+	packet, err := rlp.EncodeToBytes(txs[:3])
+	assert.NoError(t, err)
+	r := reader2.NewReader(packet)
+	allocs := testing.AllocsPerRun(100, func() {
+		r.Reset(packet)
+		dst, err = DecodeTxsPacketInto(r, dst)
+	})
+	assert.NoError(t, err)
+	assert.Zero(t, allocs)
+
+	// pooled txs are reset
+	legacyTx, err := rlp.EncodeToBytes(txs[0])
+	assert.NoError(t, err)
+	tx := GetCustomTx()
+	assert.NoError(t, DecodeTxInto(reader2.NewReader(legacyTx), tx))
+	PutCustomTx(tx)
+	assert.Nil(t, tx.SignedRlpBytes)
+	assert.Nil(t, tx.Value)
+}
+
+func TestDecodeTxInto_FromTx(t *testing.T) {
+	txs := tx256TxsForTests(t)
+	want := make([][]byte, len(txs))
+	encoded := make([][]byte, len(txs))
+	for i, tx := range txs {
+		var err error
+		want[i], err = rlp.EncodeToBytes(tx)
+		assert.NoError(t, err)
+		encoded[i] = common.CopyBytes(want[i])
+	}
+	// decoding into a tx built with FromTx, once reset, does not modify the go-ethereum tx it was built from
+	tx := new(CustomTx)
+	for _, from := range txs {
+		for j := range txs {
+			assert.NoError(t, tx.FromTx(from))
+			tx.Reset()
+			assert.NoError(t, DecodeTxInto(reader2.NewReader(encoded[j]), tx))
+		}
+	}
+	for i, tx := range txs {
+		got, err := rlp.EncodeToBytes(tx)
+		assert.NoError(t, err)
+		assert.Equal(t, want[i], got, "tx %d", i)
+	}
+}
+
+func BenchmarkDecodeTxsPacket(b *testing.B) {
+	packet, err := rlp.EncodeToBytes(fuzzSeedTxsForTests(b))
+	assert.NoError(b, err)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err = DecodeTxsPacket(reader2.NewReader(packet)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeTxsPacketInto(b *testing.B) {
+	packet, err := rlp.EncodeToBytes(fuzzSeedTxsForTests(b))
+	assert.NoError(b, err)
+	var txs []*CustomTx
+	r := reader2.NewReader(packet)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.Reset(packet)
+		if txs, err = DecodeTxsPacketInto(r, txs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
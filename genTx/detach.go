@@ -0,0 +1,110 @@
+package genTx
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"slices"
+)
+
+// Detach copies the bytes the decoded tx points to, SignedRlpBytes and Data, so the tx stays valid after the decoded
+// bytes are modified or reused. Data is sliced out of the copy of SignedRlpBytes when it is part of them. The rest of
+// the values are already copied when decoding.
+// To decode txs that never point to the decoded bytes see reader.NewCopyReader.
+func (tx *CustomTx) Detach() {
+	signed := tx.SignedRlpBytes
+	tx.SignedRlpBytes = common.CopyBytes(signed)
+	tx.Data = detachData(tx.Data, signed, tx.SignedRlpBytes, tx.startTxDataPointer)
+}
+
+// Clone returns a copy of the tx that shares no memory with it, neither the decoded bytes nor the values that Reset
+// keeps to be reused, so the copy stays valid after the tx is reset or put back in the pool.
+func (tx *CustomTx) Clone() *CustomTx {
+	c := *tx
+	c.spare = spareValues{}
+	c.Detach()
+	c.UnsignedRlpBytes = common.CopyBytes(tx.UnsignedRlpBytes)
+	c.signedHash = common.CopyBytes(tx.signedHash)
+	c.unsignedHash = common.CopyBytes(tx.unsignedHash)
+	c.from = common.CopyBytes(tx.from)
+	for _, v := range [...]**big.Int{&c.GasPrice, &c.Value, &c.V, &c.R, &c.S, &c.ChainID, &c.GasTipCap, &c.GasFeeCap, &c.BlobFeeCap} {
+		if *v != nil {
+			*v = new(big.Int).Set(*v)
+		}
+	}
+	if tx.To != nil {
+		to := *tx.To
+		c.To = &to
+	}
+	c.AccessList = cloneAccessList(tx.AccessList)
+	c.BlobHashes = slices.Clone(tx.BlobHashes)
+	c.AuthList = slices.Clone(tx.AuthList)
+	c.Sidecar = cloneBlobTxSidecar(tx.Sidecar)
+	return &c
+}
+
+// Detach copies the bytes the decoded tx points to, see CustomTx.Detach
+func (tx *Tx256) Detach() {
+	signed := tx.SignedRlpBytes
+	tx.SignedRlpBytes = common.CopyBytes(signed)
+	tx.Data = detachData(tx.Data, signed, tx.SignedRlpBytes, tx.startTxDataPointer)
+}
+
+// detachData returns data sliced out of detached, the copy of signed, when data is part of signed, where it is looked
+// for from the offset start. Otherwise, e.g. when decoded with a copy reader, data is copied.
+func detachData(data, signed, detached []byte, start int) []byte {
+	if len(data) == 0 {
+		return common.CopyBytes(data)
+	}
+	for i := max(start, 0); i+len(data) <= len(signed); i++ {
+		if &signed[i] == &data[0] {
+			return detached[i : i+len(data) : i+len(data)]
+		}
+	}
+	return common.CopyBytes(data)
+}
+
+// Clone returns a copy of the tx that shares no memory with it, see CustomTx.Clone
+func (tx *Tx256) Clone() *Tx256 {
+	c := *tx
+	c.Detach()
+	if tx.To != nil {
+		to := *tx.To
+		c.To = &to
+	}
+	c.AccessList = cloneAccessList(tx.AccessList)
+	c.BlobHashes = slices.Clone(tx.BlobHashes)
+	c.AuthList = slices.Clone(tx.AuthList)
+	c.Sidecar = cloneBlobTxSidecar(tx.Sidecar)
+	return &c
+}
+
+// Detach copies the bytes of the tx, so it stays valid after the decoded bytes are modified or reused. Data, the only
+// decoded field pointing to them, is decoded again from the copy when accessed.
+func (tx *LazyTx) Detach() {
+	tx.tx.SignedRlpBytes = common.CopyBytes(tx.tx.SignedRlpBytes)
+	tx.tx.Data = nil
+	tx.decoded &^= 1 << fieldData
+}
+
+func cloneAccessList(accessList types.AccessList) types.AccessList {
+	if accessList == nil {
+		return nil
+	}
+	c := make(types.AccessList, len(accessList))
+	for i, tuple := range accessList {
+		c[i] = types.AccessTuple{Address: tuple.Address, StorageKeys: slices.Clone(tuple.StorageKeys)}
+	}
+	return c
+}
+
+func cloneBlobTxSidecar(sidecar *types.BlobTxSidecar) *types.BlobTxSidecar {
+	if sidecar == nil {
+		return nil
+	}
+	return &types.BlobTxSidecar{
+		Blobs:       slices.Clone(sidecar.Blobs),
+		Commitments: slices.Clone(sidecar.Commitments),
+		Proofs:      slices.Clone(sidecar.Proofs),
+	}
+}
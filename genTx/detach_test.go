@@ -0,0 +1,151 @@
+package genTx
+
+import (
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// overwriteForTests reuses the buffer the txs were decoded from
+func overwriteForTests(b []byte) {
+	for i := range b {
+		b[i] = 0xff
+	}
+}
+
+// pointsIntoForTests checks whether b is part of the bytes of buf
+func pointsIntoForTests(b, buf []byte) bool {
+	for i := 0; len(b) > 0 && i+len(b) <= len(buf); i++ {
+		if &buf[i] == &b[0] {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCustomTx_Detach(t *testing.T) {
+	txs := tx256TxsForTests(t)
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(t, err)
+
+	decode := func(r *reader.RlpReader) []*CustomTx {
+		decoded, err := DecodeTxsPacket(r)
+		assert.NoError(t, err)
+		assert.Len(t, decoded, len(txs))
+		return decoded
+	}
+	aliased := decode(reader.NewReader(packet))
+	detached := decode(reader.NewReader(packet))
+	cloned := decode(reader.NewReader(packet))
+	copied := decode(reader.NewCopyReader(packet))
+	for i := range txs {
+		detached[i].Detach()
+		cloned[i] = cloned[i].Clone()
+	}
+	overwriteForTests(packet)
+
+	for i, want := range txs {
+		// the data of the detached txs is still part of their rlp bytes, instead of a copy of its own
+		if len(want.Data()) > 0 {
+			assert.True(t, pointsIntoForTests(detached[i].Data, detached[i].SignedRlpBytes), "tx %d", i)
+		}
+		// the txs that are not detached point to the overwritten bytes
+		aliased[i].signedHash = nil
+		assert.NotEqual(t, want.Hash(), aliased[i].Hash())
+		for _, got := range []*CustomTx{detached[i], cloned[i], copied[i]} {
+			compareTxFieldsForTests(t, got, want)
+			// calculate them again from the rlp bytes
+			got.signedHash, got.unsignedHash, got.from = nil, nil, nil
+			assert.Equal(t, want.Hash(), got.Hash())
+			from, err := got.From()
+			assert.NoError(t, err)
+			assert.Equal(t, senderForTests(t, want), from)
+		}
+	}
+}
+
+func TestCustomTx_Clone(t *testing.T) {
+	txs := tx256TxsForTests(t)
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(t, err)
+	decoded, err := DecodeTxsPacket(reader.NewReader(packet))
+	assert.NoError(t, err)
+	cloned := make([]*CustomTx, len(decoded))
+	for i, tx := range decoded {
+		cloned[i] = tx.Clone()
+	}
+	// the values of the decoded txs are reused by decoding other txs into them
+	reversed, err := rlp.EncodeToBytes(append(txs[len(txs)-1:], txs[:len(txs)-1]...))
+	assert.NoError(t, err)
+	_, err = DecodeTxsPacketInto(reader.NewReader(reversed), decoded)
+	assert.NoError(t, err)
+	overwriteForTests(packet)
+
+	for i, want := range txs {
+		compareTxFieldsForTests(t, cloned[i], want)
+		assert.Equal(t, want.Hash(), cloned[i].Hash())
+	}
+}
+
+func TestTx256_Detach(t *testing.T) {
+	txs := tx256TxsForTests(t)
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(t, err)
+
+	decode := func(r *reader.RlpReader) []*Tx256 {
+		decoded, err := DecodeTxsPacket256(r)
+		assert.NoError(t, err)
+		assert.Len(t, decoded, len(txs))
+		return decoded
+	}
+	detached := decode(reader.NewReader(packet))
+	cloned := decode(reader.NewReader(packet))
+	copied := decode(reader.NewCopyReader(packet))
+	for i := range txs {
+		detached[i].Detach()
+		cloned[i] = cloned[i].Clone()
+	}
+	overwriteForTests(packet)
+
+	for i, want := range txs {
+		if len(want.Data()) > 0 {
+			assert.True(t, pointsIntoForTests(detached[i].Data, detached[i].SignedRlpBytes), "tx %d", i)
+		}
+		for _, got := range []*Tx256{detached[i], cloned[i], copied[i]} {
+			compareTx256ForTests(t, got, want)
+			got.hasSignedHash, got.hasUnsignedHash, got.hasFrom = false, false, false
+			assert.Equal(t, want.Hash(), got.Hash())
+			from, err := got.From()
+			assert.NoError(t, err)
+			assert.Equal(t, senderForTests(t, want), from)
+		}
+	}
+}
+
+func TestLazyTx_Detach(t *testing.T) {
+	txs := tx256TxsForTests(t)
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(t, err)
+	lazyTxs, err := DecodeLazyTxsPacket(reader.NewReader(packet))
+	assert.NoError(t, err)
+	for _, tx := range lazyTxs {
+		// decoded before detaching it
+		_, err = tx.Data()
+		assert.NoError(t, err)
+		tx.Detach()
+	}
+	overwriteForTests(packet)
+
+	for i, want := range txs {
+		tx := lazyTxs[i]
+		data, err := tx.Data()
+		assert.NoError(t, err)
+		assert.Equal(t, want.Data(), data)
+		value, err := tx.Value()
+		assert.NoError(t, err)
+		assert.Equal(t, want.Value(), value)
+		tx.tx.signedHash, tx.tx.unsignedHash = nil, nil
+		assert.Equal(t, want.Hash(), tx.Hash())
+	}
+}
@@ -3,6 +3,7 @@ package genTx
 import (
 	"bytes"
 	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -28,6 +29,104 @@ func EncodeTxsPacket(buffer *bytes.Buffer, txs []*CustomTx) error {
 	return nil
 }
 
+// AppendTxsPacket appends the txs encoded as a list of signed txs to dst, as EncodeTxsPacket writes them, and returns
+// the extended slice. dst is only reallocated when its capacity is not enough.
+func AppendTxsPacket(dst []byte, txs []*CustomTx) ([]byte, error) {
+	listLength, err := txsPacketLength(txs)
+	if err != nil {
+		return dst, err
+	}
+	start := len(dst)
+	dst = AppendListLength(dst, listLength)
+	for _, tx := range txs {
+		if dst, err = tx.AppendSignedRLP(dst); err != nil {
+			return dst[:start], err
+		}
+	}
+	return dst, nil
+}
+
+// EncodePooledTxsPacket writes the txs as a PooledTransactions response, the request id followed by the list of the
+// signed txs, as DecodePoolTxsPacket reads it. The lengths of the txs are calculated once, before writing anything,
+// so nothing is written if one of them cannot be encoded.
+func EncodePooledTxsPacket(buffer *bytes.Buffer, requestID uint64, txs []*CustomTx) error {
+	listLength, err := txsPacketLength(txs)
+	if err != nil {
+		return err
+	}
+	buffer.Grow(pooledTxsPacketLength(requestID, listLength))
+	if _, err = WriteListLength(buffer, CalculateRLP64ValueLength(requestID)+CalculateRLPListLength(listLength)); err != nil {
+		return err
+	}
+	if err = WriteRLPUint64(buffer, requestID); err != nil {
+		return err
+	}
+	if _, err = WriteListLength(buffer, listLength); err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		if err = tx.EncodeSignedRLP(buffer, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AppendPooledTxsPacket appends the txs encoded as a PooledTransactions response to dst, as EncodePooledTxsPacket
+// writes them, and returns the extended slice. On error dst is returned as it was.
+func AppendPooledTxsPacket(dst []byte, requestID uint64, txs []*CustomTx) ([]byte, error) {
+	listLength, err := txsPacketLength(txs)
+	if err != nil {
+		return dst, err
+	}
+	start := len(dst)
+	dst = AppendListLength(dst, CalculateRLP64ValueLength(requestID)+CalculateRLPListLength(listLength))
+	dst = AppendRLPUint64(dst, requestID)
+	dst = AppendListLength(dst, listLength)
+	for _, tx := range txs {
+		if dst, err = tx.AppendSignedRLP(dst); err != nil {
+			return dst[:start], err
+		}
+	}
+	return dst, nil
+}
+
+// pooledTxsPacketLength returns the length of a PooledTransactions response whose list of txs has listLength bytes
+func pooledTxsPacketLength(requestID uint64, listLength int) int {
+	return CalculateRLPListLength(CalculateRLP64ValueLength(requestID) + CalculateRLPListLength(listLength))
+}
+
+// txsPacketLength returns the length of the items of the list of the signed txs
+func txsPacketLength(txs []*CustomTx) (int, error) {
+	var listLength int
+	for _, tx := range txs {
+		if len(tx.SignedRlpBytes) > 0 {
+			listLength += len(tx.SignedRlpBytes)
+			continue
+		}
+		l, _, err := tx.CalculateRLPSignedBytesLength()
+		if err != nil {
+			return 0, err
+		}
+		listLength += l
+	}
+	return listLength, nil
+}
+
+// AppendSignedRLP appends the signed tx to dst, as EncodeSignedRLP writes it, and returns the extended slice. dst is
+// only reallocated when its capacity is not enough. On error dst is returned as it was.
+func (tx *CustomTx) AppendSignedRLP(dst []byte) ([]byte, error) {
+	if len(tx.SignedRlpBytes) > 0 {
+		return append(dst, tx.SignedRlpBytes...), nil
+	}
+	// the buffer writes after the bytes of dst, using its capacity
+	buffer := bytes.NewBuffer(dst)
+	if err := tx.EncodeSignedRLP(buffer, false); err != nil {
+		return dst, err
+	}
+	return buffer.Bytes(), nil
+}
+
 func (tx *CustomTx) EncodeSignedRLP(buffer *bytes.Buffer, save bool) error {
 	if len(tx.SignedRlpBytes) > 0 {
 		_, err := buffer.Write(tx.SignedRlpBytes)
@@ -40,6 +139,10 @@ func (tx *CustomTx) EncodeSignedRLP(buffer *bytes.Buffer, save bool) error {
 		return tx.EncodeSignedDynamicFeesTx(buffer, save)
 	case types.AccessListTxType:
 		return tx.EncodeSignedAccessListTx(buffer, save)
+	case types.SetCodeTxType:
+		return tx.EncodeSignedSetCodeTx(buffer, save)
+	case types.BlobTxType:
+		return tx.EncodeSignedBlobTx(buffer, save)
 	default:
 		return errors.ErrTxTypeNotSupported
 	}
@@ -117,6 +220,7 @@ func (tx *CustomTx) EncodeSignedLegacyTx(buffer *bytes.Buffer, save bool) (err e
 		return err
 	}
 	err = WriteRLPBytes(buffer, tx.S.Bytes())
+	tx.setTxOffsets(listValLength, txValsLength)
 
 	bufferBytes := buffer.Bytes()
 	if save {
@@ -127,8 +231,19 @@ func (tx *CustomTx) EncodeSignedLegacyTx(buffer *bytes.Buffer, save bool) (err e
 	return err
 }
 
+// setTxOffsets records where the tx values start and where the signature starts, as decoding does, so the unsigned
+// encoding and the sender can be taken from the SignedRlpBytes once they are saved
+func (tx *CustomTx) setTxOffsets(startTxDataPointer, txValsLength int) {
+	tx.startTxDataPointer = startTxDataPointer
+	tx.startTxSignature = startTxDataPointer + txValsLength - tx.CalculateRLPLengthSignatureValues()
+}
+
 func (tx *CustomTx) EncodeAccessTuple(buffer *bytes.Buffer, accessTuple types.AccessTuple) error {
-	accessTupleLength := tx.calculateRLPAccessTupleLength(accessTuple)
+	return writeAccessTuple(buffer, accessTuple)
+}
+
+func writeAccessTuple(buffer *bytes.Buffer, accessTuple types.AccessTuple) error {
+	accessTupleLength := accessTupleRLPLength(accessTuple)
 	_, err := WriteListLength(buffer, accessTupleLength)
 	if err != nil {
 		return err
@@ -157,13 +272,17 @@ func (tx *CustomTx) EncodeAccessTuple(buffer *bytes.Buffer, accessTuple types.Ac
 }
 
 func (tx *CustomTx) EncodeAccessList(buffer *bytes.Buffer) error {
-	accessListLength := tx.calculateRLPAccessListLength()
+	return writeAccessList(buffer, tx.AccessList)
+}
+
+func writeAccessList(buffer *bytes.Buffer, accessList types.AccessList) error {
+	accessListLength := accessListRLPLength(accessList)
 	_, err := WriteListLength(buffer, accessListLength)
 	if err != nil {
 		return err
 	}
-	for _, a := range tx.AccessList {
-		err = tx.EncodeAccessTuple(buffer, a)
+	for _, a := range accessList {
+		err = writeAccessTuple(buffer, a)
 		if err != nil {
 			return err
 		}
@@ -189,7 +308,8 @@ func (tx *CustomTx) EncodeSignedDynamicFeesTx(buffer *bytes.Buffer, save bool) e
 			return err
 		}
 		totalRLPLength += rlpListLength // this add the nBytes used to write the list of the tx data
-		totalRLPLength += txValsLength  // Add the length of the tx values
+		tx.setTxOffsets(totalRLPLength, txValsLength)
+		totalRLPLength += txValsLength // Add the length of the tx values
 		buffer.Write(tx.UnsignedRlpBytes)
 		// add pointer to let the hasher from which part it should start
 		tx.startTx = rlpValsLength
@@ -212,7 +332,8 @@ func (tx *CustomTx) EncodeSignedDynamicFeesTx(buffer *bytes.Buffer, save bool) e
 			return err
 		}
 		totalRLPLength += rlpListLength // this add the nBytes used to write the list of the tx data
-		totalRLPLength += txValsLength  // Add the length of the tx values
+		tx.setTxOffsets(totalRLPLength, txValsLength)
+		totalRLPLength += txValsLength // Add the length of the tx values
 		err = WriteRLPBytes(buffer, tx.ChainID.Bytes())
 		if err != nil {
 			return err
@@ -313,7 +434,8 @@ func (tx *CustomTx) EncodeSignedAccessListTx(buffer *bytes.Buffer, save bool) er
 			return err
 		}
 		totalRLPLength += rlpListLength // this add the nBytes used to write the list of the tx data
-		totalRLPLength += txValsLength  // Add the length of the tx values
+		tx.setTxOffsets(totalRLPLength, txValsLength)
+		totalRLPLength += txValsLength // Add the length of the tx values
 		buffer.Write(tx.UnsignedRlpBytes)
 		// add pointer to let the hasher from which part it should start
 		tx.startTx = rlpValsLength
@@ -336,7 +458,8 @@ func (tx *CustomTx) EncodeSignedAccessListTx(buffer *bytes.Buffer, save bool) er
 			return err
 		}
 		totalRLPLength += rlpListLength // this add the nBytes used to write the list of the tx data
-		totalRLPLength += txValsLength  // Add the length of the tx values
+		tx.setTxOffsets(totalRLPLength, txValsLength)
+		totalRLPLength += txValsLength // Add the length of the tx values
 		err = WriteRLPBytes(buffer, tx.ChainID.Bytes())
 		if err != nil {
 			return err
@@ -414,20 +537,21 @@ func (tx *CustomTx) EncodeSignedAccessListTx(buffer *bytes.Buffer, save bool) er
 }
 
 func (tx *CustomTx) EncodeUnsignedLegacyTx(buffer *bytes.Buffer) error {
+	signerValues := tx.Signer().signerValues
 	if len(tx.SignedRlpBytes) > 0 {
 		// we just get the length of the tx without the signed part
-		txValsLength := tx.startTxSignature - tx.startTxDataPointer + SIGNER_VALUES_LENGTH
+		txValsLength := tx.startTxSignature - tx.startTxDataPointer + len(signerValues)
 		_, err := WriteListLength(buffer, txValsLength)
 		if err != nil {
 			return err
 		}
 		buffer.Write(tx.SignedRlpBytes[tx.startTxDataPointer:tx.startTxSignature])
-		buffer.Write(SIGNER_VALUES)
+		buffer.Write(signerValues)
 	} else {
 		// when doing this it that the tx is a new tx (not signed). If we are doing this most probably we are sending this tx
 		// so we store the rlp bytes.
 		txValsLength := tx.calculateRLPUnsignedBytesLenLegacyTx()
-		_, err := WriteListLength(buffer, txValsLength+SIGNER_VALUES_LENGTH)
+		_, err := WriteListLength(buffer, txValsLength+len(signerValues))
 		if err != nil {
 			return err
 		}
@@ -476,7 +600,7 @@ func (tx *CustomTx) EncodeUnsignedLegacyTx(buffer *bytes.Buffer) error {
 		// copy this data to the unsigned rlp bytes
 		copy(tx.UnsignedRlpBytes, buffer.Bytes()[len(buffer.Bytes())-txValsLength:])
 		// add signer stuff
-		_, err = buffer.Write(SIGNER_VALUES)
+		_, err = buffer.Write(signerValues)
 	}
 
 	return nil
@@ -667,3 +791,614 @@ func (tx *CustomTx) EncodeUnsignedAccessListTx(buffer *bytes.Buffer) error {
 		return err
 	}
 }
+
+func (tx *CustomTx) EncodeBlobHashes(buffer *bytes.Buffer) error {
+	return writeBlobHashes(buffer, tx.BlobHashes)
+}
+
+func writeBlobHashes(buffer *bytes.Buffer, blobHashes []common.Hash) error {
+	_, err := WriteListLength(buffer, len(blobHashes)*HashRLPLength)
+	if err != nil {
+		return err
+	}
+	for _, h := range blobHashes {
+		err = buffer.WriteByte(EncodedHashRLPLength)
+		if err != nil {
+			return err
+		}
+		_, err = buffer.Write(h.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tx *CustomTx) EncodeUnsignedBlobTx(buffer *bytes.Buffer) error {
+
+	if len(tx.SignedRlpBytes) > 0 {
+		txValsLength := tx.startTxSignature - tx.startTxDataPointer
+		err := buffer.WriteByte(tx.TxType)
+		if err != nil {
+			return err
+		}
+		_, err = WriteListLength(buffer, txValsLength)
+		if err != nil {
+			return err
+		}
+		_, err = buffer.Write(tx.SignedRlpBytes[tx.startTxDataPointer:tx.startTxSignature])
+		return err
+	} else {
+
+		// notice that we dont att the list value length prefix, since this is only used for getting the unsigned hash
+		// also we dont need the prefix when encoding the signed tx
+		txValsLength := tx.calculateRLPUnSignedBytesLenBlobTx()
+
+		buffer.WriteByte(tx.TxType)
+
+		_, err := WriteListLength(buffer, txValsLength)
+		if err != nil {
+			return err
+		}
+		err = WriteRLPBytes(buffer, tx.ChainID.Bytes())
+		if err != nil {
+			return err
+		}
+		// we have already written the length indicating list length of the tx
+		// now we have to write every value.
+		err = WriteRLPUint64(buffer, tx.Nonce)
+		if err != nil {
+			return err
+		}
+
+		err = WriteRLPBytes(buffer, tx.GasTipCap.Bytes())
+		if err != nil {
+			return err
+		}
+
+		err = WriteRLPBytes(buffer, tx.GasFeeCap.Bytes())
+		if err != nil {
+			return err
+		}
+
+		err = WriteRLPUint64(buffer, tx.Gas)
+		if err != nil {
+			return err
+		}
+
+		if tx.To != nil {
+			err = WriteRLPBytes(buffer, tx.To.Bytes())
+		} else {
+			err = buffer.WriteByte(ZeroUint64RLPVal)
+		}
+		if err != nil {
+			return err
+		}
+
+		if tx.Value == nil {
+			err = buffer.WriteByte(ZeroUint64RLPVal)
+		} else {
+			err = WriteRLPBytes(buffer, tx.Value.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+
+		err = WriteRLPBytes(buffer, tx.Data)
+		if err != nil {
+			return err
+		}
+
+		if len(tx.AccessList) > 0 {
+			err = tx.EncodeAccessList(buffer)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = buffer.WriteByte(ZeroListRLPVal)
+			if err != nil {
+				return err
+			}
+		}
+
+		if tx.BlobFeeCap == nil {
+			err = buffer.WriteByte(ZeroUint64RLPVal)
+		} else {
+			err = WriteRLPBytes(buffer, tx.BlobFeeCap.Bytes())
+		}
+		if err != nil {
+			return err
+		}
+
+		err = tx.EncodeBlobHashes(buffer)
+		if err != nil {
+			return err
+		}
+		bufferBytes := buffer.Bytes()
+		tx.UnsignedRlpBytes = make([]byte, txValsLength)
+		// copy this data to the unsigned rlp bytes
+		copy(tx.UnsignedRlpBytes, bufferBytes[len(bufferBytes)-txValsLength:])
+		return err
+	}
+}
+
+// EncodeBlobTxSidecar writes the blobs, commitments and proofs lists of the network form of a blob tx
+func (tx *CustomTx) EncodeBlobTxSidecar(buffer *bytes.Buffer) error {
+	return writeBlobTxSidecar(buffer, tx.Sidecar)
+}
+
+func writeBlobTxSidecar(buffer *bytes.Buffer, sidecar *types.BlobTxSidecar) error {
+	blobsLength, commitmentsLength, proofsLength := blobTxSidecarRLPLengths(sidecar)
+	_, err := WriteListLength(buffer, blobsLength)
+	if err != nil {
+		return err
+	}
+	for i := range sidecar.Blobs {
+		err = WriteRLPBytes(buffer, sidecar.Blobs[i][:])
+		if err != nil {
+			return err
+		}
+	}
+	_, err = WriteListLength(buffer, commitmentsLength)
+	if err != nil {
+		return err
+	}
+	for i := range sidecar.Commitments {
+		err = WriteRLPBytes(buffer, sidecar.Commitments[i][:])
+		if err != nil {
+			return err
+		}
+	}
+	_, err = WriteListLength(buffer, proofsLength)
+	if err != nil {
+		return err
+	}
+	for i := range sidecar.Proofs {
+		err = WriteRLPBytes(buffer, sidecar.Proofs[i][:])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EncodeSignedBlobTx writes the signed blob tx. When the tx has a sidecar it is written in its network form
+// (tx payload, blobs, commitments, proofs), otherwise in its canonical form.
+func (tx *CustomTx) EncodeSignedBlobTx(buffer *bytes.Buffer, save bool) error {
+
+	var totalRLPLength int
+
+	// length of the tx values + the signature vals (v,r,s)
+	txValsLength := tx.calculateRLPSignedBytesLenBlobTx()
+	// length of the list that follows the txtype
+	innerLength := CalculateRLPListLength(txValsLength)
+	// in the network form the tx payload list and the sidecar lists are wrapped in another list
+	var networkPayloadLength int
+	if tx.Sidecar != nil {
+		networkPayloadLength = innerLength + tx.calculateRLPBlobTxSidecarLength()
+		innerLength = CalculateRLPListLength(networkPayloadLength)
+	}
+
+	// write first the rlp value of the txtype + txvals
+	rlpValsLength, err := WriteValLength(buffer, innerLength+1)
+	if err != nil {
+		return err
+	}
+	totalRLPLength += rlpValsLength // this adds the nBytes used to write the size of the tx
+	// write the txtype
+	buffer.WriteByte(tx.TxType)
+	totalRLPLength += 1
+	// add pointer to let the hasher from which part it should start
+	tx.startTx = rlpValsLength
+	tx.startBlobTxPayload = 0
+	tx.endBlobTxPayload = 0
+
+	if tx.Sidecar != nil {
+		rlpOuterListLength, err := WriteListLength(buffer, networkPayloadLength)
+		if err != nil {
+			return err
+		}
+		totalRLPLength += rlpOuterListLength
+		tx.startBlobTxPayload = totalRLPLength
+	}
+
+	rlpListLength, err := WriteListLength(buffer, txValsLength)
+	if err != nil {
+		return err
+	}
+	totalRLPLength += rlpListLength // this add the nBytes used to write the list of the tx data
+	tx.setTxOffsets(totalRLPLength, txValsLength)
+	totalRLPLength += txValsLength // Add the length of the tx values
+	if tx.Sidecar != nil {
+		tx.endBlobTxPayload = totalRLPLength
+	}
+	if len(tx.UnsignedRlpBytes) > 0 {
+		buffer.Write(tx.UnsignedRlpBytes)
+	} else {
+		err = WriteRLPBytes(buffer, tx.ChainID.Bytes())
+		if err != nil {
+			return err
+		}
+		// we have already written the length indicating list length of the tx
+		// now we have to write every value.
+		err = WriteRLPUint64(buffer, tx.Nonce)
+		if err != nil {
+			return err
+		}
+
+		err = WriteRLPBytes(buffer, tx.GasTipCap.Bytes())
+		if err != nil {
+			return err
+		}
+
+		err = WriteRLPBytes(buffer, tx.GasFeeCap.Bytes())
+		if err != nil {
+			return err
+		}
+
+		err = WriteRLPUint64(buffer, tx.Gas)
+		if err != nil {
+			return err
+		}
+
+		if tx.To != nil {
+			err = WriteRLPBytes(buffer, tx.To.Bytes())
+		} else {
+			err = buffer.WriteByte(ZeroUint64RLPVal)
+		}
+		if err != nil {
+			return err
+		}
+
+		if tx.Value == nil {
+			err = buffer.WriteByte(ZeroUint64RLPVal)
+		} else {
+			err = WriteRLPBytes(buffer, tx.Value.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+
+		err = WriteRLPBytes(buffer, tx.Data)
+		if err != nil {
+			return err
+		}
+
+		if len(tx.AccessList) > 0 {
+			err = tx.EncodeAccessList(buffer)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = buffer.WriteByte(ZeroListRLPVal)
+			if err != nil {
+				return err
+			}
+		}
+
+		if tx.BlobFeeCap == nil {
+			err = buffer.WriteByte(ZeroUint64RLPVal)
+		} else {
+			err = WriteRLPBytes(buffer, tx.BlobFeeCap.Bytes())
+		}
+		if err != nil {
+			return err
+		}
+
+		err = tx.EncodeBlobHashes(buffer)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = WriteRLPBytes(buffer, tx.V.Bytes())
+	if err != nil {
+		return err
+	}
+	err = WriteRLPBytes(buffer, tx.R.Bytes())
+	if err != nil {
+		return err
+	}
+	err = WriteRLPBytes(buffer, tx.S.Bytes())
+	if err != nil {
+		return err
+	}
+	if tx.Sidecar != nil {
+		err = tx.EncodeBlobTxSidecar(buffer)
+		if err != nil {
+			return err
+		}
+		totalRLPLength += tx.calculateRLPBlobTxSidecarLength()
+	}
+	if save {
+		bufferBytes := buffer.Bytes()
+		tx.SignedRlpBytes = make([]byte, totalRLPLength)
+		copy(tx.SignedRlpBytes, bufferBytes[len(bufferBytes)-totalRLPLength:])
+	}
+	tx.rlpSignedBytesLength = totalRLPLength
+	return err
+}
+
+func (tx *CustomTx) EncodeSetCodeAuthorization(buffer *bytes.Buffer, auth *types.SetCodeAuthorization) error {
+	return writeSetCodeAuthorization(buffer, auth)
+}
+
+func writeSetCodeAuthorization(buffer *bytes.Buffer, auth *types.SetCodeAuthorization) error {
+	_, err := WriteListLength(buffer, authorizationRLPLength(auth))
+	if err != nil {
+		return err
+	}
+	err = WriteRLPBytes(buffer, auth.ChainID.Bytes())
+	if err != nil {
+		return err
+	}
+	err = buffer.WriteByte(EncodedAddressRLPLength)
+	if err != nil {
+		return err
+	}
+	_, err = buffer.Write(auth.Address.Bytes())
+	if err != nil {
+		return err
+	}
+	err = WriteRLPUint64(buffer, auth.Nonce)
+	if err != nil {
+		return err
+	}
+	err = WriteRLPUint64(buffer, uint64(auth.V))
+	if err != nil {
+		return err
+	}
+	err = WriteRLPBytes(buffer, auth.R.Bytes())
+	if err != nil {
+		return err
+	}
+	return WriteRLPBytes(buffer, auth.S.Bytes())
+}
+
+func (tx *CustomTx) EncodeAuthList(buffer *bytes.Buffer) error {
+	return writeAuthList(buffer, tx.AuthList)
+}
+
+func writeAuthList(buffer *bytes.Buffer, authList []types.SetCodeAuthorization) error {
+	_, err := WriteListLength(buffer, authListRLPLength(authList))
+	if err != nil {
+		return err
+	}
+	for i := range authList {
+		err = writeSetCodeAuthorization(buffer, &authList[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tx *CustomTx) EncodeSignedSetCodeTx(buffer *bytes.Buffer, save bool) error {
+
+	var totalRLPLength int
+
+	if len(tx.UnsignedRlpBytes) > 0 {
+		txValsLength := len(tx.UnsignedRlpBytes) + tx.CalculateRLPLengthSignatureValues()
+		rlpValsLength, err := WriteValLength(buffer, CalculateRLPListLength(txValsLength)+1)
+		if err != nil {
+			return err
+		}
+		totalRLPLength += rlpValsLength
+		buffer.WriteByte(tx.TxType)
+		totalRLPLength += 1
+		rlpListLength, err := WriteListLength(buffer, txValsLength)
+		if err != nil {
+			return err
+		}
+		totalRLPLength += rlpListLength // this add the nBytes used to write the list of the tx data
+		tx.setTxOffsets(totalRLPLength, txValsLength)
+		totalRLPLength += txValsLength // Add the length of the tx values
+		buffer.Write(tx.UnsignedRlpBytes)
+		// add pointer to let the hasher from which part it should start
+		tx.startTx = rlpValsLength
+	} else {
+		// length of the tx values + the signature vals (v,r,s)
+		txValsLength := tx.calculateRLPSignedBytesLenSetCodeTx()
+
+		// write first the rlp value of the txtype + txvals
+		rlpValsLength, err := WriteValLength(buffer, CalculateRLPListLength(txValsLength)+1)
+		if err != nil {
+			return err
+		}
+		totalRLPLength += rlpValsLength // this adds the nBytes used to write the size of the tx
+		// write the txtype
+		buffer.WriteByte(tx.TxType)
+		totalRLPLength += 1
+
+		rlpListLength, err := WriteListLength(buffer, txValsLength)
+		if err != nil {
+			return err
+		}
+		totalRLPLength += rlpListLength // this add the nBytes used to write the list of the tx data
+		tx.setTxOffsets(totalRLPLength, txValsLength)
+		totalRLPLength += txValsLength // Add the length of the tx values
+		err = WriteRLPBytes(buffer, tx.ChainID.Bytes())
+		if err != nil {
+			return err
+		}
+		// we have already written the length indicating list length of the tx
+		// now we have to write every value.
+		err = WriteRLPUint64(buffer, tx.Nonce)
+		if err != nil {
+			return err
+		}
+
+		err = WriteRLPBytes(buffer, tx.GasTipCap.Bytes())
+		if err != nil {
+			return err
+		}
+
+		err = WriteRLPBytes(buffer, tx.GasFeeCap.Bytes())
+		if err != nil {
+			return err
+		}
+
+		err = WriteRLPUint64(buffer, tx.Gas)
+		if err != nil {
+			return err
+		}
+
+		if tx.To != nil {
+			err = WriteRLPBytes(buffer, tx.To.Bytes())
+		} else {
+			err = buffer.WriteByte(ZeroUint64RLPVal)
+		}
+		if err != nil {
+			return err
+		}
+
+		if tx.Value == nil {
+			err = buffer.WriteByte(ZeroUint64RLPVal)
+		} else {
+			err = WriteRLPBytes(buffer, tx.Value.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+
+		err = WriteRLPBytes(buffer, tx.Data)
+		if err != nil {
+			return err
+		}
+
+		if len(tx.AccessList) > 0 {
+			err = tx.EncodeAccessList(buffer)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = buffer.WriteByte(ZeroListRLPVal)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = tx.EncodeAuthList(buffer)
+		if err != nil {
+			return err
+		}
+		// add pointer to let the hasher from which part it should start
+		tx.startTx = rlpValsLength
+	}
+
+	err := WriteRLPBytes(buffer, tx.V.Bytes())
+	if err != nil {
+		return err
+	}
+	err = WriteRLPBytes(buffer, tx.R.Bytes())
+	if err != nil {
+		return err
+	}
+	err = WriteRLPBytes(buffer, tx.S.Bytes())
+	if save {
+		bufferBytes := buffer.Bytes()
+		tx.SignedRlpBytes = make([]byte, totalRLPLength)
+		copy(tx.SignedRlpBytes, bufferBytes[len(bufferBytes)-totalRLPLength:])
+	}
+	tx.rlpSignedBytesLength = totalRLPLength
+	return err
+}
+
+func (tx *CustomTx) EncodeUnsignedSetCodeTx(buffer *bytes.Buffer) error {
+
+	if len(tx.SignedRlpBytes) > 0 {
+		txValsLength := tx.startTxSignature - tx.startTxDataPointer
+		err := buffer.WriteByte(tx.TxType)
+		if err != nil {
+			return err
+		}
+		_, err = WriteListLength(buffer, txValsLength)
+		if err != nil {
+			return err
+		}
+		_, err = buffer.Write(tx.SignedRlpBytes[tx.startTxDataPointer:tx.startTxSignature])
+		return err
+	} else {
+
+		// notice that we dont att the list value length prefix, since this is only used for getting the unsigned hash
+		// also we dont need the prefix when encoding the signed tx
+		txValsLength := tx.calculateRLPUnSignedBytesLenSetCodeTx()
+
+		buffer.WriteByte(tx.TxType)
+
+		_, err := WriteListLength(buffer, txValsLength)
+		if err != nil {
+			return err
+		}
+		err = WriteRLPBytes(buffer, tx.ChainID.Bytes())
+		if err != nil {
+			return err
+		}
+		// we have already written the length indicating list length of the tx
+		// now we have to write every value.
+		err = WriteRLPUint64(buffer, tx.Nonce)
+		if err != nil {
+			return err
+		}
+
+		err = WriteRLPBytes(buffer, tx.GasTipCap.Bytes())
+		if err != nil {
+			return err
+		}
+
+		err = WriteRLPBytes(buffer, tx.GasFeeCap.Bytes())
+		if err != nil {
+			return err
+		}
+
+		err = WriteRLPUint64(buffer, tx.Gas)
+		if err != nil {
+			return err
+		}
+
+		if tx.To != nil {
+			err = WriteRLPBytes(buffer, tx.To.Bytes())
+		} else {
+			err = buffer.WriteByte(ZeroUint64RLPVal)
+		}
+		if err != nil {
+			return err
+		}
+
+		if tx.Value == nil {
+			err = buffer.WriteByte(ZeroUint64RLPVal)
+		} else {
+			err = WriteRLPBytes(buffer, tx.Value.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+
+		err = WriteRLPBytes(buffer, tx.Data)
+		if err != nil {
+			return err
+		}
+
+		if len(tx.AccessList) > 0 {
+			err = tx.EncodeAccessList(buffer)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = buffer.WriteByte(ZeroListRLPVal)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = tx.EncodeAuthList(buffer)
+		if err != nil {
+			return err
+		}
+		bufferBytes := buffer.Bytes()
+		tx.UnsignedRlpBytes = make([]byte, txValsLength)
+		// copy this data to the unsigned rlp bytes
+		copy(tx.UnsignedRlpBytes, bufferBytes[len(bufferBytes)-txValsLength:])
+		return err
+	}
+}
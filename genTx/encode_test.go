@@ -1,10 +1,15 @@
 package genTx
 
 import (
+	"bytes"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
 	"github.com/1aBcD1234aBcD1/prlp/pool"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
 	"math/big"
 	"testing"
@@ -482,3 +487,178 @@ func TestCustomTx_EncodeSignedDynamicFeetTx(t *testing.T) {
 		})
 	}
 }
+
+func TestCustomTx_EncodeSignedSetCodeTx(t *testing.T) {
+	privKey, err := getPrivkeyForTests()
+	if err != nil {
+		t.Fatalf("Failed to get private key: %v", err)
+	}
+	auth1, err := types.SignSetCode(privKey, types.SetCodeAuthorization{
+		ChainID: *uint256.NewInt(56),
+		Address: common.Address{0xde, 0xad, 0xde, 0xad},
+		Nonce:   123131,
+	})
+	if err != nil {
+		t.Fatalf("Failed to sign authorization: %v", err)
+	}
+	auth2, err := types.SignSetCode(privKey, types.SetCodeAuthorization{
+		Address: common.Address{0xbe, 0xef},
+		Nonce:   0,
+	})
+	if err != nil {
+		t.Fatalf("Failed to sign authorization: %v", err)
+	}
+
+	tests := []struct {
+		Name      string
+		CustomTx  *CustomTx
+		WantError error
+	}{
+		{
+			Name: "Test set code tx with one authorization",
+			CustomTx: &CustomTx{
+				ChainID:   big.NewInt(56),
+				Nonce:     100,
+				To:        &common.Address{0x00, 0x01, 0x02, 0x03, 0x04, 0x05},
+				Gas:       21000,
+				GasTipCap: big.NewInt(10000000000),
+				GasFeeCap: big.NewInt(10000000000),
+				TxType:    types.SetCodeTxType,
+				AuthList:  []types.SetCodeAuthorization{auth1},
+			},
+		},
+		{
+			Name: "Test set code tx with several authorizations, data and access list",
+			CustomTx: &CustomTx{
+				ChainID:   big.NewInt(56),
+				Nonce:     100,
+				Value:     big.NewInt(1000000000000000000),
+				To:        &common.Address{0x00, 0x01, 0x02, 0x03, 0x04, 0x05},
+				Gas:       21000,
+				Data:      []byte{0x01, 0x02, 0x03, 0x04, 0x05},
+				GasTipCap: big.NewInt(10000000000),
+				GasFeeCap: big.NewInt(10000000000),
+				TxType:    types.SetCodeTxType,
+				AccessList: types.AccessList{
+					{
+						Address: common.Address{0x00, 0x01, 0x02, 0x03, 0x04, 0x05},
+						StorageKeys: []common.Hash{
+							common.Hash{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f},
+						},
+					},
+				},
+				AuthList: []types.SetCodeAuthorization{auth1, auth2},
+			},
+		},
+		{
+			Name: "Test set code tx with big data",
+			CustomTx: &CustomTx{
+				ChainID:   big.NewInt(56),
+				Nonce:     100,
+				To:        &common.Address{0x00, 0x01, 0x02, 0x03, 0x04, 0x05},
+				Gas:       21000,
+				Data:      bytes.Repeat([]byte{0xfa}, 300),
+				GasTipCap: big.NewInt(10000000000),
+				GasFeeCap: big.NewInt(10000000000),
+				TxType:    types.SetCodeTxType,
+				AuthList:  []types.SetCodeAuthorization{auth2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			Init(tt.CustomTx.ChainID)
+			err = tt.CustomTx.SignTx(privKey)
+			if err != nil {
+				t.Fatalf("Failed to sign transaction: %v", err)
+			}
+
+			l, _, err := tt.CustomTx.CalculateRLPSignedBytesLength()
+			assert.NoError(t, err)
+
+			buf := pool.GetRLPBuffer()
+			defer pool.PutRLPBuffer(buf)
+			err = tt.CustomTx.EncodeSignedRLP(buf, true)
+			assert.NoError(t, err)
+			assert.Equal(t, l, buf.Len(), "precomputed length not equal")
+
+			var want *types.Transaction
+			err = rlp.DecodeBytes(buf.Bytes(), &want)
+			if err != nil {
+				t.Fatalf("Failed to decode signed RLP: %v", err)
+			}
+
+			if tt.WantError != nil {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.WantError)
+			} else {
+				assert.Equal(t, want.Type(), tt.CustomTx.TxType)
+				compareSetCodeTx(t, tt.CustomTx, want)
+			}
+
+			signer := types.LatestSignerForChainID(tt.CustomTx.ChainID)
+			assert.Equal(t, signer.Hash(want), tt.CustomTx.UnsignedHash(), "unsigned hash not equal")
+			assert.Equal(t, want.Hash(), tt.CustomTx.Hash(), "hash not equal")
+
+			wantRLP, err := rlp.EncodeToBytes(want)
+			if err != nil {
+				t.Fatalf("Failed to encode signed RLP: %v", err)
+			}
+			assert.Equal(t, wantRLP, buf.Bytes(), "signed RLP not equal")
+		})
+	}
+}
+
+func TestEncodePooledTxsPacket(t *testing.T) {
+	txs := tx256TxsForTests(t)
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(t, err)
+	decoded, err := DecodeTxsPacket(reader.NewReader(packet))
+	assert.NoError(t, err)
+	fromFields := make([]*CustomTx, len(decoded))
+	for i, tx := range decoded {
+		fromFields[i] = exportedFieldsForTests(tx)
+	}
+
+	for _, requestID := range []uint64{0, 1, 0x80, 1<<64 - 1} {
+		for _, encoded := range [][]*CustomTx{decoded, fromFields, {}} {
+			want, err := rlp.EncodeToBytes(eth.PooledTransactionsPacket{
+				RequestId:                  requestID,
+				PooledTransactionsResponse: eth.PooledTransactionsResponse(txs[:len(encoded)]),
+			})
+			assert.NoError(t, err)
+
+			buffer := new(bytes.Buffer)
+			assert.NoError(t, EncodePooledTxsPacket(buffer, requestID, encoded))
+			assert.Equal(t, want, buffer.Bytes(), "request id %d", requestID)
+			got, err := AppendPooledTxsPacket([]byte{0x01}, requestID, encoded)
+			assert.NoError(t, err)
+			assert.Equal(t, append([]byte{0x01}, want...), got, "request id %d", requestID)
+			buffer.Reset()
+			assert.NoError(t, NewEncoder(buffer).EncodePooledTxsPacket(requestID, encoded))
+			assert.Equal(t, want, buffer.Bytes(), "request id %d", requestID)
+
+			// decoded back as it is received
+			r := reader.NewReader(want)
+			gotTxs, err := DecodePoolTxsPacket(r)
+			assert.NoError(t, err)
+			assert.Zero(t, r.Len())
+			assert.Len(t, gotTxs, len(encoded))
+			for i, tx := range gotTxs {
+				assert.Equal(t, txs[i].Hash(), tx.Hash())
+			}
+		}
+	}
+
+	// nothing is written when a tx cannot be encoded
+	withUnsupported := append(decoded[:len(decoded):len(decoded)], &CustomTx{TxType: 0x7f})
+	buffer := new(bytes.Buffer)
+	assert.ErrorIs(t, EncodePooledTxsPacket(buffer, 1, withUnsupported), errors.ErrTxTypeNotSupported)
+	assert.Zero(t, buffer.Len())
+	got, err := AppendPooledTxsPacket([]byte{0x01}, 1, withUnsupported)
+	assert.ErrorIs(t, err, errors.ErrTxTypeNotSupported)
+	assert.Equal(t, []byte{0x01}, got)
+	assert.ErrorIs(t, NewEncoder(buffer).EncodePooledTxsPacket(1, withUnsupported), errors.ErrTxTypeNotSupported)
+	assert.Zero(t, buffer.Len())
+}
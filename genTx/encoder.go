@@ -0,0 +1,111 @@
+package genTx
+
+import (
+	"io"
+)
+
+const (
+	// encoderScratchSize is the initial capacity of the buffer of an Encoder
+	encoderScratchSize = 512
+	// encoderMaxScratchSize is the largest buffer kept by an Encoder after writing, larger ones are dropped
+	encoderMaxScratchSize = 64 * 1024
+	// encoderDirectWriteSize is the length from which the rlp bytes of a tx are written to the writer as they are,
+	// instead of being copied into the buffer
+	encoderDirectWriteSize = 256
+)
+
+// Encoder writes rlp encoded txs to an io.Writer, like a connection, without encoding them into a bytes.Buffer
+// first. Headers, small txs and txs without SignedRlpBytes are gathered in a small internal buffer, and the rlp bytes
+// of the rest are written as they are. Every call writes what it encodes before returning. As the packets are not
+// buffered whole, a call that fails after writing part of them leaves it written, so w should be discarded then.
+//
+// An Encoder is not safe for concurrent use.
+type Encoder struct {
+	w       io.Writer
+	scratch []byte
+}
+
+// NewEncoder creates an Encoder writing to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, scratch: make([]byte, 0, encoderScratchSize)}
+}
+
+// Reset makes the encoder write to w, keeping its buffer
+func (e *Encoder) Reset(w io.Writer) {
+	e.w = w
+	e.scratch = e.scratch[:0]
+}
+
+// EncodeTx writes the signed tx, as EncodeSignedRLP does. Nothing is written if it cannot be encoded.
+func (e *Encoder) EncodeTx(tx *CustomTx) error {
+	if err := e.appendTx(tx); err != nil {
+		e.scratch = e.scratch[:0]
+		return err
+	}
+	return e.flush()
+}
+
+// EncodeTxsPacket writes the txs as a list of signed txs, as EncodeTxsPacket does. Nothing is written if the length
+// of a tx cannot be calculated, but part of the packet may already be written when encoding a tx or writing to w
+// fails.
+func (e *Encoder) EncodeTxsPacket(txs []*CustomTx) error {
+	listLength, err := txsPacketLength(txs)
+	if err != nil {
+		return err
+	}
+	e.scratch = AppendListLength(e.scratch[:0], listLength)
+	for _, tx := range txs {
+		if err = e.appendTx(tx); err != nil {
+			e.scratch = e.scratch[:0]
+			return err
+		}
+	}
+	return e.flush()
+}
+
+// EncodePooledTxsPacket writes the txs as a PooledTransactions response, as EncodePooledTxsPacket does. Nothing is
+// written if the length of a tx cannot be calculated, but part of the packet may already be written when encoding a
+// tx or writing to w fails.
+func (e *Encoder) EncodePooledTxsPacket(requestID uint64, txs []*CustomTx) error {
+	listLength, err := txsPacketLength(txs)
+	if err != nil {
+		return err
+	}
+	e.scratch = AppendListLength(e.scratch[:0], CalculateRLP64ValueLength(requestID)+CalculateRLPListLength(listLength))
+	e.scratch = AppendRLPUint64(e.scratch, requestID)
+	e.scratch = AppendListLength(e.scratch, listLength)
+	for _, tx := range txs {
+		if err = e.appendTx(tx); err != nil {
+			e.scratch = e.scratch[:0]
+			return err
+		}
+	}
+	return e.flush()
+}
+
+// appendTx adds the signed tx to the buffer, or writes the buffer and the tx when its rlp bytes are large enough
+func (e *Encoder) appendTx(tx *CustomTx) (err error) {
+	if len(tx.SignedRlpBytes) >= encoderDirectWriteSize {
+		if err = e.flush(); err != nil {
+			return err
+		}
+		_, err = e.w.Write(tx.SignedRlpBytes)
+		return err
+	}
+	e.scratch, err = tx.AppendSignedRLP(e.scratch)
+	return err
+}
+
+// flush writes the buffer, dropping it if it has grown too much
+func (e *Encoder) flush() error {
+	if len(e.scratch) == 0 {
+		return nil
+	}
+	_, err := e.w.Write(e.scratch)
+	if cap(e.scratch) > encoderMaxScratchSize {
+		e.scratch = make([]byte, 0, encoderScratchSize)
+	} else {
+		e.scratch = e.scratch[:0]
+	}
+	return err
+}
@@ -0,0 +1,252 @@
+package genTx
+
+import (
+	"bytes"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"math/big"
+	"testing"
+)
+
+func TestAppendRLPValues(t *testing.T) {
+	for _, i := range []uint64{0, 1, 0x7f, 0x80, 0xff, 0x100, 1 << 32, 1<<64 - 1} {
+		want, err := rlp.EncodeToBytes(i)
+		assert.NoError(t, err)
+		assert.Equal(t, want, AppendRLPUint64(nil, i), "uint64 %d", i)
+		u := uint256.NewInt(i)
+		assert.Equal(t, want, AppendRLPUint256(nil, u), "uint256 %d", i)
+	}
+	big256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	want, err := rlp.EncodeToBytes(big256)
+	assert.NoError(t, err)
+	assert.Equal(t, want, AppendRLPUint256(nil, uint256.MustFromBig(big256)))
+
+	for _, n := range []int{0, 1, 55, 56, 255, 256, 1 << 16} {
+		data := bytes.Repeat([]byte{0x80}, n)
+		want, err := rlp.EncodeToBytes(data)
+		assert.NoError(t, err)
+		// appended after the bytes already in dst
+		got := AppendRLPBytes([]byte{0x01}, data)
+		assert.Equal(t, append([]byte{0x01}, want...), got, "bytes of length %d", n)
+
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, WriteRLPBytes(buffer, data))
+		assert.Equal(t, want, buffer.Bytes(), "bytes of length %d", n)
+
+		list, err := rlp.EncodeToBytes([]rlp.RawValue{want})
+		assert.NoError(t, err)
+		assert.Equal(t, list, append(AppendListLength(nil, len(want)), want...))
+	}
+	assert.Equal(t, []byte{0x7f}, AppendRLPBytes(nil, []byte{0x7f}))
+}
+
+func TestCustomTx_AppendSignedRLP(t *testing.T) {
+	txs := tx256TxsForTests(t)
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(t, err)
+	decoded, err := DecodeTxsPacket(reader.NewReader(packet))
+	assert.NoError(t, err)
+
+	for i, want := range txs {
+		enc, err := rlp.EncodeToBytes(want)
+		assert.NoError(t, err)
+		// encoded from the fields and from the rlp bytes
+		fromFields := exportedFieldsForTests(decoded[i])
+		for _, tx := range []*CustomTx{fromFields, decoded[i]} {
+			prefix := []byte{0x01, 0x02}
+			got, err := tx.AppendSignedRLP(prefix)
+			assert.NoError(t, err)
+			assert.Equal(t, append([]byte{0x01, 0x02}, enc...), got)
+		}
+		tx256, err := DecodeTx256(reader.NewReader(enc))
+		assert.NoError(t, err)
+		tx256.SignedRlpBytes = nil
+		got, err := tx256.AppendSignedRLP(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, enc, got)
+	}
+
+	got, err := AppendTxsPacket(nil, decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, packet, got)
+	txs256, err := DecodeTxsPacket256(reader.NewReader(packet))
+	assert.NoError(t, err)
+	got, err = AppendTxsPacket256(nil, txs256)
+	assert.NoError(t, err)
+	assert.Equal(t, packet, got)
+
+	// appending into a large enough slice does not allocate
+	dst := make([]byte, 0, len(packet))
+	allocs := testing.AllocsPerRun(10, func() {
+		if dst, err = AppendTxsPacket(dst[:0], decoded); err != nil {
+			t.Fatal(err)
+		}
+	})
+	assert.Zero(t, allocs)
+
+	// dst is left as it was on error
+	unsupported := &CustomTx{TxType: 0x7f}
+	got, err = unsupported.AppendSignedRLP([]byte{0x01})
+	assert.ErrorIs(t, err, errors.ErrTxTypeNotSupported)
+	assert.Equal(t, []byte{0x01}, got)
+	got, err = AppendTxsPacket([]byte{0x01}, append(decoded, unsupported))
+	assert.ErrorIs(t, err, errors.ErrTxTypeNotSupported)
+	assert.Equal(t, []byte{0x01}, got)
+}
+
+// writesCounterForTests counts the writes done to the buffer
+type writesCounterForTests struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *writesCounterForTests) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestEncoder(t *testing.T) {
+	txs := tx256TxsForTests(t)
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(t, err)
+	decoded, err := DecodeTxsPacket(reader.NewReader(packet))
+	assert.NoError(t, err)
+	fromFields := make([]*CustomTx, len(decoded))
+	for i, tx := range decoded {
+		fromFields[i] = exportedFieldsForTests(tx)
+	}
+
+	w := new(writesCounterForTests)
+	e := NewEncoder(w)
+	for _, encoded := range [][]*CustomTx{decoded, fromFields} {
+		w.Reset()
+		w.writes = 0
+		assert.NoError(t, e.EncodeTxsPacket(encoded))
+		assert.Equal(t, packet, w.Bytes())
+	}
+	// the small txs are gathered with the list header, the large blob tx is written as it is
+	assert.Equal(t, 1, w.writes)
+	w.writes = 0
+	assert.NoError(t, e.EncodeTxsPacket(decoded))
+	assert.Equal(t, 2, w.writes)
+
+	for i, want := range txs {
+		enc, err := rlp.EncodeToBytes(want)
+		assert.NoError(t, err)
+		w.Reset()
+		assert.NoError(t, e.EncodeTx(decoded[i]))
+		assert.Equal(t, enc, w.Bytes())
+	}
+
+	// nothing is written when a tx cannot be encoded
+	w.Reset()
+	err = e.EncodeTxsPacket(append(decoded, &CustomTx{TxType: 0x7f}))
+	assert.ErrorIs(t, err, errors.ErrTxTypeNotSupported)
+	assert.Zero(t, w.Len())
+	assert.ErrorIs(t, e.EncodeTx(&CustomTx{TxType: 0x7f}), errors.ErrTxTypeNotSupported)
+	assert.Zero(t, w.Len())
+
+	err = NewEncoder(failingWriterForTests{}).EncodeTxsPacket(decoded)
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+
+	// the packet is partially written when the writer fails after the first write
+	partial := &limitedWriterForTests{writes: 1}
+	err = NewEncoder(partial).EncodeTxsPacket(decoded)
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+	assert.NotZero(t, partial.Len())
+	assert.Less(t, partial.Len(), len(packet))
+}
+
+// limitedWriterForTests fails once it has accepted its writes
+type limitedWriterForTests struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *limitedWriterForTests) Write(p []byte) (int, error) {
+	if w.writes == 0 {
+		return 0, io.ErrClosedPipe
+	}
+	w.writes--
+	return w.Buffer.Write(p)
+}
+
+type failingWriterForTests struct{}
+
+func (failingWriterForTests) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+// packetBenchTxsForTests returns the txs of a packet as decoded and without their SignedRlpBytes, which are encoded
+// from their fields
+func packetBenchTxsForTests(b *testing.B) (decoded, fromFields []*CustomTx) {
+	txs := fuzzSeedTxsForTests(b)
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(b, err)
+	decoded, err = DecodeTxsPacket(reader.NewReader(packet))
+	assert.NoError(b, err)
+	fromFields, _ = encodeBenchTxsForTests(b)
+	return decoded, fromFields
+}
+
+func BenchmarkEncodeTxsPacket_Buffer(b *testing.B) {
+	decoded, fromFields := packetBenchTxsForTests(b)
+	for _, bench := range []struct {
+		name string
+		txs  []*CustomTx
+	}{{"decoded", decoded}, {"fields", fromFields}} {
+		b.Run(bench.name, func(b *testing.B) {
+			buffer := new(bytes.Buffer)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buffer.Reset()
+				if err := EncodeTxsPacket(buffer, bench.txs); err != nil {
+					b.Fatal(err)
+				}
+				// the bytes are sent, which copies them from the buffer
+				_, _ = io.Discard.Write(buffer.Bytes())
+			}
+		})
+	}
+}
+
+func BenchmarkEncodeTxsPacket_Append(b *testing.B) {
+	decoded, fromFields := packetBenchTxsForTests(b)
+	for _, bench := range []struct {
+		name string
+		txs  []*CustomTx
+	}{{"decoded", decoded}, {"fields", fromFields}} {
+		b.Run(bench.name, func(b *testing.B) {
+			var dst []byte
+			var err error
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if dst, err = AppendTxsPacket(dst[:0], bench.txs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkEncodeTxsPacket_Encoder(b *testing.B) {
+	decoded, fromFields := packetBenchTxsForTests(b)
+	for _, bench := range []struct {
+		name string
+		txs  []*CustomTx
+	}{{"decoded", decoded}, {"fields", fromFields}} {
+		b.Run(bench.name, func(b *testing.B) {
+			e := NewEncoder(io.Discard)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := e.EncodeTxsPacket(bench.txs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
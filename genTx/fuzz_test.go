@@ -0,0 +1,257 @@
+package genTx
+
+import (
+	"bytes"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+// fuzzSeedTxsForTests returns a signed tx of every supported type, used to build the seed packets of the fuzz targets
+func fuzzSeedTxsForTests(f testing.TB) []*types.Transaction {
+	privKey, err := getPrivkeyForTests()
+	if err != nil {
+		f.Fatalf("Failed to get private key: %v", err)
+	}
+	to := common.HexToAddress("0x2f2a2543b76a4166549f7aab2e75bef0aefc5b0f")
+	accessList := types.AccessList{{Address: to, StorageKeys: []common.Hash{{0x01}, {0x02}}}}
+	auth, err := types.SignSetCode(privKey, types.SetCodeAuthorization{ChainID: *uint256.NewInt(1), Address: to, Nonce: 1})
+	if err != nil {
+		f.Fatalf("Failed to sign authorization: %v", err)
+	}
+	txsData := []types.TxData{
+		&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1e9), Gas: 21000, To: &to, Value: big.NewInt(1)},
+		&types.AccessListTx{ChainID: big.NewInt(1), Nonce: 2, GasPrice: big.NewInt(1e9), Gas: 21000, To: &to,
+			Data: []byte{0xde, 0xad}, AccessList: accessList},
+		&types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 3, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1e9),
+			Gas: 21000, To: &to, AccessList: accessList},
+		&types.BlobTx{ChainID: uint256.NewInt(1), Nonce: 4, GasTipCap: uint256.NewInt(1), GasFeeCap: uint256.NewInt(1e9),
+			Gas: 21000, To: to, BlobFeeCap: uint256.NewInt(1), BlobHashes: []common.Hash{{0x01}}},
+		&types.SetCodeTx{ChainID: uint256.NewInt(1), Nonce: 5, GasTipCap: uint256.NewInt(1), GasFeeCap: uint256.NewInt(1e9),
+			Gas: 21000, To: to, AuthList: []types.SetCodeAuthorization{auth}},
+	}
+	txs := make([]*types.Transaction, 0, len(txsData))
+	for _, txData := range txsData {
+		tx, err := types.SignNewTx(privKey, types.LatestSignerForChainID(big.NewInt(1)), txData)
+		if err != nil {
+			f.Fatalf("Failed to sign tx: %v", err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+// fuzzDecodedTxs checks that the decoded txs can be used without panicking
+func fuzzDecodedTxs(txs []*CustomTx) {
+	for _, tx := range txs {
+		tx.Hash()
+	}
+}
+
+func FuzzDecodeTxsPacket(f *testing.F) {
+	txs := fuzzSeedTxsForTests(f)
+	for i := range txs {
+		b, err := rlp.EncodeToBytes(txs[:i+1])
+		if err != nil {
+			f.Fatalf("Failed to RLP encode transactions: %v", err)
+		}
+		f.Add(b)
+	}
+	f.Add([]byte{})
+	f.Add([]byte{0xc0})
+	// legacy tx list longer than the packet
+	f.Add([]byte{0xc2, 0xf8, 0xff})
+	// blob tx whose payload list is shorter than its fields
+	f.Add([]byte{0xc5, 0x84, 0x03, 0xc2, 0xc0, 0x80})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, strict := range []bool{false, true} {
+			r := reader.NewReader(data)
+			r.SetStrict(strict)
+			decoded, _ := DecodeTxsPacket(r)
+			fuzzDecodedTxs(decoded)
+
+			s := reader.NewStreamReader(bytes.NewReader(data), 64)
+			s.SetStrict(strict)
+			decoded, _ = DecodeTxsPacketStream(s)
+			fuzzDecodedTxs(decoded)
+		}
+	})
+}
+
+func FuzzDecodePoolTxsPacket(f *testing.F) {
+	txs := fuzzSeedTxsForTests(f)
+	for i := range txs {
+		b, err := rlp.EncodeToBytes(eth.PooledTransactionsPacket{
+			RequestId:                  uint64(i),
+			PooledTransactionsResponse: txs[:i+1],
+		})
+		if err != nil {
+			f.Fatalf("Failed to RLP encode transactions: %v", err)
+		}
+		f.Add(b)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, strict := range []bool{false, true} {
+			r := reader.NewReader(data)
+			r.SetStrict(strict)
+			decoded, _ := DecodePoolTxsPacket(r)
+			fuzzDecodedTxs(decoded)
+
+			s := reader.NewStreamReader(bytes.NewReader(data), 64)
+			s.SetStrict(strict)
+			decoded, _ = DecodePoolTxsPacketStream(s)
+			fuzzDecodedTxs(decoded)
+		}
+	})
+}
+
+// exportedFieldsForTests returns a tx with only the exported fields of tx, without the rlp bytes, so encoding it
+// goes through the encoders
+func exportedFieldsForTests(tx *CustomTx) *CustomTx {
+	return &CustomTx{
+		TxType:     tx.TxType,
+		Nonce:      tx.Nonce,
+		GasPrice:   tx.GasPrice,
+		Gas:        tx.Gas,
+		To:         tx.To,
+		Value:      tx.Value,
+		Data:       tx.Data,
+		V:          tx.V,
+		R:          tx.R,
+		S:          tx.S,
+		ChainID:    tx.ChainID,
+		GasTipCap:  tx.GasTipCap,
+		GasFeeCap:  tx.GasFeeCap,
+		AccessList: tx.AccessList,
+		BlobFeeCap: tx.BlobFeeCap,
+		Sidecar:    tx.Sidecar,
+		BlobHashes: tx.BlobHashes,
+		AuthList:   tx.AuthList,
+	}
+}
+
+// compareTxFieldsForTests checks that the fields decoded by prlp are the ones decoded by go-ethereum. Lists are
+// compared through their rlp encoding, since go-ethereum decodes empty lists as non nil slices.
+func compareTxFieldsForTests(t *testing.T, got *CustomTx, want *types.Transaction) {
+	rlpEqual := func(want, got interface{}, msg string) {
+		wantRlp, err := rlp.EncodeToBytes(want)
+		assert.NoError(t, err)
+		gotRlp, err := rlp.EncodeToBytes(got)
+		assert.NoError(t, err)
+		assert.Equal(t, wantRlp, gotRlp, msg)
+	}
+	assert.Equal(t, want.Type(), got.TxType, "type not equal")
+	assert.Equal(t, want.Nonce(), got.Nonce, "nonce not equal")
+	assert.Equal(t, want.Gas(), got.Gas, "gas not equal")
+	assert.Equal(t, want.To(), got.To, "to not equal")
+	assert.Equal(t, want.Value().String(), got.Value.String(), "value not equal")
+	assert.Equal(t, want.Data(), []byte(got.Data), "data not equal")
+	v, r, s := want.RawSignatureValues()
+	assert.Equal(t, v.String(), got.V.String(), "v not equal")
+	assert.Equal(t, r.String(), got.R.String(), "r not equal")
+	assert.Equal(t, s.String(), got.S.String(), "s not equal")
+	switch want.Type() {
+	case types.LegacyTxType:
+		assert.Equal(t, want.GasPrice().String(), got.GasPrice.String(), "gas price not equal")
+		return
+	case types.AccessListTxType:
+		assert.Equal(t, want.GasPrice().String(), got.GasPrice.String(), "gas price not equal")
+	default:
+		assert.Equal(t, want.GasTipCap().String(), got.GasTipCap.String(), "gas tip cap not equal")
+		assert.Equal(t, want.GasFeeCap().String(), got.GasFeeCap.String(), "gas fee cap not equal")
+	}
+	assert.Equal(t, want.ChainId().String(), got.ChainID.String(), "chain id not equal")
+	rlpEqual(want.AccessList(), got.AccessList, "access list not equal")
+	switch want.Type() {
+	case types.BlobTxType:
+		assert.Equal(t, want.BlobGasFeeCap().String(), got.BlobFeeCap.String(), "blob fee cap not equal")
+		rlpEqual(want.BlobHashes(), got.BlobHashes, "blob hashes not equal")
+		if want.BlobTxSidecar() != nil {
+			rlpEqual(want.BlobTxSidecar(), got.Sidecar, "sidecar not equal")
+		} else {
+			assert.Nil(t, got.Sidecar)
+		}
+	case types.SetCodeTxType:
+		rlpEqual(want.SetCodeAuthorizations(), got.AuthList, "auth list not equal")
+	}
+}
+
+// FuzzDecodeTx decodes single txs in their wire encoding. For every tx that go-ethereum accepts prlp must decode
+// the same fields, hash and sender, and encoding the decoded fields again must give the same bytes.
+func FuzzDecodeTx(f *testing.F) {
+	txs := fuzzSeedTxsForTests(f)
+	for _, tx := range txs {
+		b, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			f.Fatalf("Failed to RLP encode transaction: %v", err)
+		}
+		f.Add(b)
+	}
+	// blob tx in its network form
+	b, err := rlp.EncodeToBytes(newSignedBlobTxForTests(f, 1))
+	if err != nil {
+		f.Fatalf("Failed to RLP encode transaction: %v", err)
+	}
+	f.Add(b)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		want := new(types.Transaction)
+		if err := rlp.DecodeBytes(data, want); err != nil {
+			// go-ethereum rejects it, we only check that decoding it does not panic
+			_, _ = DecodeTx(reader.NewReader(data))
+			if tx, err := DecodeTx256(reader.NewReader(data)); err == nil {
+				tx.Hash()
+			}
+			return
+		}
+		r := reader.NewStrictReader(data)
+		got, err := DecodeTx(r)
+		if !assert.NoError(t, err, "rejected a tx accepted by go-ethereum") {
+			return
+		}
+		assert.Equal(t, uint64(0), r.Len(), "not all data consumed")
+		assert.Equal(t, data, got.SignedRlpBytes, "rlp bytes not equal")
+		assert.Equal(t, want.Hash(), got.Hash(), "hash not equal")
+		compareTxFieldsForTests(t, got, want)
+
+		var signer types.Signer = types.HomesteadSigner{}
+		if want.Protected() {
+			signer = types.LatestSignerForChainID(want.ChainId())
+		}
+		if wantFrom, err := types.Sender(signer, want); err == nil {
+			from, err := got.From()
+			assert.NoError(t, err, "sender not recovered")
+			assert.Equal(t, wantFrom, from, "sender not equal")
+		}
+
+		buffer := new(bytes.Buffer)
+		reEncoded := exportedFieldsForTests(got)
+		if assert.NoError(t, reEncoded.EncodeSignedRLP(buffer, false)) {
+			assert.Equal(t, data, buffer.Bytes(), "re-encoding does not round trip")
+		}
+		assert.Equal(t, want.Hash(), reEncoded.Hash(), "hash of the re-encoded tx not equal")
+
+		got256, err := DecodeTx256(reader.NewStrictReader(data))
+		if !assert.NoError(t, err, "Tx256 rejected a tx accepted by go-ethereum") {
+			return
+		}
+		compareTx256ForTests(t, got256, want)
+		if wantFrom, err := types.Sender(signer, want); err == nil {
+			from, err := got256.From()
+			assert.NoError(t, err, "Tx256 sender not recovered")
+			assert.Equal(t, wantFrom, from, "Tx256 sender not equal")
+		}
+		// encode the fields instead of writing the decoded bytes
+		got256.SignedRlpBytes, got256.hasSignedHash = nil, false
+		buffer.Reset()
+		if assert.NoError(t, got256.EncodeSignedRLP(buffer, false)) {
+			assert.Equal(t, data, buffer.Bytes(), "Tx256 re-encoding does not round trip")
+		}
+		assert.Equal(t, want.Hash(), got256.Hash(), "hash of the re-encoded Tx256 not equal")
+	})
+}
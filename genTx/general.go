@@ -1,53 +1,46 @@
 package genTx
 
 import (
-	"bytes"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
 	"math/big"
 )
 
 // Here it is defined global variables and the initialization of other variables
-var CHAIN_ID = big.NewInt(1).Int64()
 var EncodedAddressRLPLength = byte(0x94)
 var EncodedHashRLPLength = byte(0xa0)
 var AddressRLPLength = 20 + 1
 var HashRLPLength = 32 + 1
-var V_MULTIPLIER = big.NewInt(CHAIN_ID * 2)
-var V_MULTIPLER_INT64 = V_MULTIPLIER.Int64()
-var V_MULTIPLER_UINT64 = V_MULTIPLIER.Uint64()
+
+// Deprecated: the chain values of chain 1, they are no longer updated by Init. Use DefaultSigner or the Signer of
+// the tx instead.
+var (
+	CHAIN_ID           = big.NewInt(1).Int64()
+	V_MULTIPLIER       = big.NewInt(CHAIN_ID * 2)
+	V_MULTIPLER_INT64  = V_MULTIPLIER.Int64()
+	V_MULTIPLER_UINT64 = V_MULTIPLIER.Uint64()
+)
 
 var (
 	secp256k1N, _       = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
 	secp256k1NBytes     = secp256k1N.Bytes()
 	secp256k1halfN      = new(big.Int).Div(secp256k1N, big.NewInt(2))
 	secp256k1halfNBytes = secp256k1halfN.Bytes()
+	secp256k1NU256      = uint256.MustFromBig(secp256k1N)
+	secp256k1halfNU256  = uint256.MustFromBig(secp256k1halfN)
 	BYTE_1              = common.Big1.Bytes()
+	big27               = big.NewInt(27)
+	big35               = big.NewInt(35)
 )
 
+// Deprecated: never set, use the Signer of the tx instead.
 var (
 	SIGNER_VALUES        []byte
 	SIGNER_VALUES_LENGTH int
 )
 
+// Init sets the process wide default signer, see DefaultSigner. It is only used for txs that have no signer,
+// signature or chain id of their own, see CustomTx.Signer. It is safe to call while txs are being processed.
 func Init(chainId *big.Int) {
-	CHAIN_ID = chainId.Int64()
-	V_MULTIPLIER = big.NewInt(CHAIN_ID * 2)
-	V_MULTIPLER_INT64 = V_MULTIPLIER.Int64()
-	V_MULTIPLER_UINT64 = V_MULTIPLIER.Uint64()
-	calculateSignerValues()
-}
-
-func calculateSignerValues() {
-	l := new(big.Int).SetInt64(CHAIN_ID)
-	result := make([]byte, 0, 10)
-	buf := bytes.NewBuffer(result)
-	_, err := WriteUint64(buf, l.Uint64())
-	if err != nil {
-		panic(err)
-	}
-	buf.WriteByte(0x80)
-	buf.WriteByte(0x80)
-
-	SIGNER_VALUES = buf.Bytes()
-	SIGNER_VALUES_LENGTH = len(SIGNER_VALUES)
+	defaultSigner.Store(NewSigner(chainId))
 }
@@ -96,3 +96,43 @@ func (tx *CustomTx) MarshalJson() ([]byte, error) {
 	return json.Marshal(aux)
 
 }
+
+// UnmarshalJSON decodes the tx from the same json as CustomTx. Integers that do not fit in 256 bits are rejected.
+func (tx *Tx256) UnmarshalJSON(data []byte) error {
+	var c CustomTx
+	if err := c.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	return tx.FromCustomTx(&c)
+}
+
+// MarshalJSON encodes the tx as CustomTx.MarshalJson does
+func (tx *Tx256) MarshalJSON() ([]byte, error) {
+	var aux auxCustomTx
+	aux.Type = hexutil.Uint64(tx.TxType)
+	aux.To = tx.To
+	// only include from if it has been previously calculated
+	if tx.hasFrom {
+		aux.From = tx.from
+	}
+	aux.Nonce = hexutil.Uint64(tx.Nonce)
+	aux.Gas = hexutil.Uint64(tx.Gas)
+	aux.Value = (*hexutil.Big)(tx.Value.ToBig())
+	aux.Data = tx.Data
+	aux.V = (*hexutil.Big)(tx.V.ToBig())
+	aux.R = (*hexutil.Big)(tx.R.ToBig())
+	aux.S = (*hexutil.Big)(tx.S.ToBig())
+	if tx.TxType != types.LegacyTxType {
+		aux.ChainId = (*hexutil.Big)(tx.ChainID.ToBig())
+	}
+	if tx.TxType == types.LegacyTxType || tx.TxType == types.AccessListTxType {
+		aux.GasPrice = (*hexutil.Big)(tx.GasPrice.ToBig())
+	} else {
+		aux.GasTipCap = (*hexutil.Big)(tx.GasTipCap.ToBig())
+		aux.GasFeeCap = (*hexutil.Big)(tx.GasFeeCap.ToBig())
+	}
+	aux.AccessList = tx.AccessList
+	aux.AuthList = tx.AuthList
+
+	return json.Marshal(aux)
+}
@@ -0,0 +1,165 @@
+package genTx
+
+import (
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/core/types"
+	"io"
+)
+
+// txField identifies a field of a tx in the layouts used by LazyTx and Tx256
+type txField uint8
+
+const (
+	fieldChainID txField = iota
+	fieldNonce
+	fieldGasPrice
+	fieldGasTipCap
+	fieldGasFeeCap
+	fieldGas
+	fieldTo
+	fieldValue
+	fieldData
+	fieldAccessList
+	fieldBlobFeeCap
+	fieldBlobHashes
+	fieldAuthList
+	fieldV
+	fieldR
+	fieldS
+	fieldSidecar
+)
+
+var txFieldNames = [...]string{"chainId", "nonce", "gasPrice", "gasTipCap", "gasFeeCap", "gas", "to", "value",
+	"data", "accessList", "blobFeeCap", "blobHashes", "authList", "v", "r", "s", "sidecar"}
+
+// maxTxFields is the number of fields of the tx type with most of them, the blob tx
+const maxTxFields = 14
+
+// txLayouts holds the fields of every tx type, in the order they are encoded. The last three are always V, R and S.
+var txLayouts = map[uint8][]txField{
+	types.LegacyTxType: {fieldNonce, fieldGasPrice, fieldGas, fieldTo, fieldValue, fieldData, fieldV, fieldR, fieldS},
+	types.AccessListTxType: {fieldChainID, fieldNonce, fieldGasPrice, fieldGas, fieldTo, fieldValue, fieldData,
+		fieldAccessList, fieldV, fieldR, fieldS},
+	types.DynamicFeeTxType: {fieldChainID, fieldNonce, fieldGasTipCap, fieldGasFeeCap, fieldGas, fieldTo, fieldValue,
+		fieldData, fieldAccessList, fieldV, fieldR, fieldS},
+	types.BlobTxType: {fieldChainID, fieldNonce, fieldGasTipCap, fieldGasFeeCap, fieldGas, fieldTo, fieldValue,
+		fieldData, fieldAccessList, fieldBlobFeeCap, fieldBlobHashes, fieldV, fieldR, fieldS},
+	types.SetCodeTxType: {fieldChainID, fieldNonce, fieldGasTipCap, fieldGasFeeCap, fieldGas, fieldTo, fieldValue,
+		fieldData, fieldAccessList, fieldAuthList, fieldV, fieldR, fieldS},
+}
+
+// txParts is where the parts of a tx are in its rlp bytes, see the offsets of CustomTx
+type txParts struct {
+	txType             uint8
+	layout             []txField
+	rlpBytes           []byte
+	startTx            int
+	startTxDataPointer int
+	startTxSignature   int
+	startBlobTxPayload int
+	endBlobTxPayload   int
+}
+
+// readTxFields reads the next tx of the reader, whatever its type is, calling visit with the reader at the start of
+// every field of the layout of its type, and at the start of the sidecar of blob txs in their network form. visit
+// must consume the whole field, whose offset in the tx rlp bytes is provided.
+// Returns errors.ErrTxTypeNotSupported, after skipping it, if the tx type is not supported.
+func readTxFields(r *reader.RlpReader, visit func(field txField, offset int) error) (p txParts, err error) {
+	pos := r.Pos()
+	end := uint64(0)
+	if r.IsNextValAList() {
+		p.txType = types.LegacyTxType
+	} else {
+		valLength, err := r.ReadValueSize()
+		if err != nil {
+			return p, err
+		}
+		if !r.EnoughBytes(valLength) || valLength == 0 {
+			return p, errors.AtOffset(io.EOF, pos)
+		}
+		p.startTx = int(r.Pos() - pos)
+		end = r.Pos() + valLength
+		p.txType, _ = r.ReadByte()
+	}
+	layout, ok := txLayouts[p.txType]
+	if !ok {
+		if err = r.SkipValue(); err != nil {
+			return p, errors.WithTxType(err, int(p.txType))
+		}
+		return p, errors.ErrTxTypeNotSupported
+	}
+	p.layout = layout
+	if err = p.readList(r, pos, visit); err != nil {
+		return p, errors.WithTxType(err, int(p.txType))
+	}
+	// the tx payload must take exactly the bytes of the rlp string that wraps it
+	if end != 0 && r.Pos() != end {
+		err = errors.ErrUnexpectedLength.WithMessagef("tx payload ends at %d instead of %d", r.Pos(), end)
+		return p, errors.WithTxType(errors.AtOffset(err, pos), int(p.txType))
+	}
+	p.rlpBytes = r.GetBytes(pos, r.Pos())
+	return p, nil
+}
+
+// readList reads the tx list, which starts at the reader position. pos is where the tx, envelope included, starts.
+func (p *txParts) readList(r *reader.RlpReader, pos uint64, visit func(field txField, offset int) error) error {
+	listStart := r.Pos()
+	listSize, err := r.ReadListSize()
+	if err != nil {
+		return err
+	}
+	if !r.EnoughBytes(listSize) {
+		return errors.AtOffset(io.EOF, listStart)
+	}
+	end := r.Pos() + listSize
+	fieldsEnd := end
+	if p.txType == types.BlobTxType && r.IsNextValAList() {
+		// network form, the tx payload list is followed by the sidecar
+		p.startBlobTxPayload = int(r.Pos() - pos)
+		payloadSize, err := r.ReadListSize()
+		if err != nil {
+			return err
+		}
+		fieldsEnd = r.Pos() + payloadSize
+		p.endBlobTxPayload = int(fieldsEnd - pos)
+	}
+	p.startTxDataPointer = int(r.Pos() - pos)
+	for i, field := range p.layout {
+		if i == len(p.layout)-3 {
+			p.startTxSignature = int(r.Pos() - pos)
+		}
+		if r.Pos() >= fieldsEnd {
+			err = errors.ErrUnexpectedLength.WithMessagef("tx list ends before field %s", txFieldNames[field])
+			return errors.AtOffset(err, listStart)
+		}
+		start := r.Pos()
+		if err = visit(field, int(start-pos)); err != nil {
+			return errors.WithField(err, txFieldNames[field])
+		}
+		if r.Pos() > fieldsEnd {
+			err = errors.ErrUnexpectedLength.WithMessagef("field %s ends after the tx list", txFieldNames[field])
+			return errors.AtOffset(err, listStart)
+		}
+		if field == fieldTo && !canCreateContracts(p.txType) {
+			// checked here and not by visit, which may skip the field
+			if to, err := reader.NewReader(r.GetBytes(start, r.Pos())).DecodeNextValue(); err == nil && len(to) == 0 {
+				return errors.WithField(errEmptyTo(start), txFieldNames[field])
+			}
+		}
+	}
+	if r.Pos() != fieldsEnd {
+		err = errors.ErrUnexpectedLength.WithMessagef("tx list ends at %d instead of %d", r.Pos(), fieldsEnd)
+		return errors.AtOffset(err, listStart)
+	}
+	if fieldsEnd != end {
+		if err = visit(fieldSidecar, p.endBlobTxPayload); err != nil {
+			return errors.WithField(err, txFieldNames[fieldSidecar])
+		}
+		if r.Pos() != end {
+			err = errors.ErrUnexpectedLength.WithMessagef("tx list ends at %d instead of %d", r.Pos(), end)
+			return errors.AtOffset(err, listStart)
+		}
+	}
+	return nil
+}
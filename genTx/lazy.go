@@ -0,0 +1,336 @@
+package genTx
+
+import (
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+// LazyTx is a view over the rlp bytes of a signed tx. Decoding it only records where every field starts, each field
+// is decoded and validated the first time it is accessed. Hash and From work over the rlp bytes as in CustomTx, and
+// ToCustomTx decodes the whole tx.
+//
+// Like CustomTx, a LazyTx points to the decoded bytes, which must not be modified while the tx is used.
+type LazyTx struct {
+	// tx holds the rlp bytes and offsets used by Hash and From, and the fields decoded so far
+	tx CustomTx
+	// starts[i] is the offset in the rlp bytes of the field i of the layout, starts[len(layout)] is where the fields end
+	starts  [maxTxFields + 1]int
+	layout  []txField
+	decoded uint32 // bit i set when the txField i has been decoded
+	strict  bool
+}
+
+// DecodeLazyTxsPacket decodes a list of transactions as LazyTx. Txs of unsupported types are skipped.
+func DecodeLazyTxsPacket(r *reader.RlpReader) ([]*LazyTx, error) {
+	var txs []*LazyTx
+	listSize, err := r.ReadListSize()
+	if err != nil {
+		return nil, err
+	}
+	cPos := r.Pos()
+	for i := 0; r.Pos()-cPos < listSize; i++ {
+		tx, err := DecodeLazyTx(r)
+		if err != nil {
+			if errors.Is(err, errors.ErrTxTypeNotSupported) {
+				continue
+			}
+			return txs, errors.WithTxIndex(err, i)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// DecodeLazyTx reads the next transaction of the reader recording where its fields are, without decoding them.
+// Returns errors.ErrTxTypeNotSupported, after skipping it, if the tx type is not supported.
+func DecodeLazyTx(r *reader.RlpReader) (*LazyTx, error) {
+	tx := &LazyTx{strict: r.Strict()}
+	pos := r.Pos()
+	i := 0
+	parts, err := readTxFields(r, func(field txField, offset int) error {
+		if field == fieldSidecar {
+			// the blobs, commitments and proofs lists, decoded by Sidecar
+			for j := 0; j < 3; j++ {
+				if err := r.SkipValue(); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		tx.starts[i] = offset
+		i++
+		err := r.SkipValue()
+		// where the field ends, which is where the next one starts
+		tx.starts[i] = int(r.Pos() - pos)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	tx.layout = parts.layout
+	tx.tx.TxType = parts.txType
+	tx.tx.SignedRlpBytes = parts.rlpBytes
+	tx.tx.startTx = parts.startTx
+	tx.tx.startTxDataPointer = parts.startTxDataPointer
+	tx.tx.startTxSignature = parts.startTxSignature
+	tx.tx.startBlobTxPayload = parts.startBlobTxPayload
+	tx.tx.endBlobTxPayload = parts.endBlobTxPayload
+	return tx, nil
+}
+
+// fieldReader returns a reader over the bytes of the tx positioned at the start of the field, which must be in the
+// layout of the tx. Its end is the end of the field, so errors keep the offsets in the tx rlp bytes.
+func (tx *LazyTx) fieldReader(field txField) (*reader.RlpReader, bool) {
+	var start, end int
+	if field == fieldSidecar {
+		if tx.tx.endBlobTxPayload == 0 {
+			return nil, false
+		}
+		start, end = tx.tx.endBlobTxPayload, len(tx.tx.SignedRlpBytes)
+	} else {
+		i := tx.fieldIndex(field)
+		if i < 0 {
+			return nil, false
+		}
+		start, end = tx.starts[i], tx.starts[i+1]
+	}
+	r := reader.NewReader(tx.tx.SignedRlpBytes[:end])
+	r.SetStrict(tx.strict)
+	_ = r.Skip(uint64(start))
+	return r, true
+}
+
+func (tx *LazyTx) fieldIndex(field txField) int {
+	for i, f := range tx.layout {
+		if f == field {
+			return i
+		}
+	}
+	return -1
+}
+
+// decode decodes the field into tx.tx, if it has not been decoded yet. Fields the tx type does not have are left nil.
+func (tx *LazyTx) decode(field txField) error {
+	if tx.decoded&(1<<field) != 0 {
+		return nil
+	}
+	r, ok := tx.fieldReader(field)
+	if !ok {
+		tx.decoded |= 1 << field
+		return nil
+	}
+	var err error
+	switch field {
+	case fieldNonce:
+		tx.tx.Nonce, err = r.DecodeUint64()
+	case fieldGas:
+		tx.tx.Gas, err = r.DecodeUint64()
+	case fieldTo:
+		var toBytes []byte
+		if toBytes, err = decodeTo(r); err == nil && len(toBytes) > 0 {
+			tx.tx.To = new(common.Address)
+			tx.tx.To.SetBytes(toBytes)
+		}
+	case fieldData:
+		tx.tx.Data, err = r.DecodeNextValue()
+	case fieldAccessList:
+		tx.tx.AccessList, err = DecodeAccessList(r)
+	case fieldBlobHashes:
+		tx.tx.BlobHashes, err = DecodeBlobHashes(r)
+	case fieldAuthList:
+		tx.tx.AuthList, err = DecodeDecodeSetCodeAuthorizationList(r)
+	case fieldSidecar:
+		tx.tx.Sidecar, err = DecodeBlobTxSidecar(r)
+	default:
+		var v []byte
+		if v, err = r.DecodeNextUint(); err == nil {
+			*tx.bigIntField(field) = new(big.Int).SetBytes(v)
+		}
+	}
+	if err != nil {
+		return errors.WithTxType(errors.WithField(err, txFieldNames[field]), int(tx.tx.TxType))
+	}
+	tx.decoded |= 1 << field
+	return nil
+}
+
+// bigIntField returns where the integer field is stored
+func (tx *LazyTx) bigIntField(field txField) **big.Int {
+	switch field {
+	case fieldChainID:
+		return &tx.tx.ChainID
+	case fieldGasPrice:
+		return &tx.tx.GasPrice
+	case fieldGasTipCap:
+		return &tx.tx.GasTipCap
+	case fieldGasFeeCap:
+		return &tx.tx.GasFeeCap
+	case fieldValue:
+		return &tx.tx.Value
+	case fieldBlobFeeCap:
+		return &tx.tx.BlobFeeCap
+	case fieldV:
+		return &tx.tx.V
+	case fieldR:
+		return &tx.tx.R
+	default:
+		return &tx.tx.S
+	}
+}
+
+func (tx *LazyTx) bigInt(field txField) (*big.Int, error) {
+	if err := tx.decode(field); err != nil {
+		return nil, err
+	}
+	return *tx.bigIntField(field), nil
+}
+
+// Type returns the tx type
+func (tx *LazyTx) Type() uint8 {
+	return tx.tx.TxType
+}
+
+// SignedRlpBytes returns the rlp bytes of the tx, as CustomTx.SignedRlpBytes
+func (tx *LazyTx) SignedRlpBytes() []byte {
+	return tx.tx.SignedRlpBytes
+}
+
+// ChainID returns the chain id of typed txs, nil for legacy txs
+func (tx *LazyTx) ChainID() (*big.Int, error) {
+	return tx.bigInt(fieldChainID)
+}
+
+func (tx *LazyTx) Nonce() (uint64, error) {
+	if err := tx.decode(fieldNonce); err != nil {
+		return 0, err
+	}
+	return tx.tx.Nonce, nil
+}
+
+// GasPrice returns the gas price of legacy and access list txs, nil for the other types
+func (tx *LazyTx) GasPrice() (*big.Int, error) {
+	return tx.bigInt(fieldGasPrice)
+}
+
+// GasTipCap returns the max priority fee of dynamic fee, blob and set code txs, nil for the other types
+func (tx *LazyTx) GasTipCap() (*big.Int, error) {
+	return tx.bigInt(fieldGasTipCap)
+}
+
+// GasFeeCap returns the max fee of dynamic fee, blob and set code txs, nil for the other types
+func (tx *LazyTx) GasFeeCap() (*big.Int, error) {
+	return tx.bigInt(fieldGasFeeCap)
+}
+
+func (tx *LazyTx) Gas() (uint64, error) {
+	if err := tx.decode(fieldGas); err != nil {
+		return 0, err
+	}
+	return tx.tx.Gas, nil
+}
+
+// To returns the recipient of the tx, nil for contract creations
+func (tx *LazyTx) To() (*common.Address, error) {
+	if err := tx.decode(fieldTo); err != nil {
+		return nil, err
+	}
+	return tx.tx.To, nil
+}
+
+func (tx *LazyTx) Value() (*big.Int, error) {
+	return tx.bigInt(fieldValue)
+}
+
+// Data returns the input of the tx, pointing to the rlp bytes
+func (tx *LazyTx) Data() ([]byte, error) {
+	if err := tx.decode(fieldData); err != nil {
+		return nil, err
+	}
+	return tx.tx.Data, nil
+}
+
+// AccessList returns the access list of typed txs, nil for legacy txs
+func (tx *LazyTx) AccessList() (types.AccessList, error) {
+	if err := tx.decode(fieldAccessList); err != nil {
+		return nil, err
+	}
+	return tx.tx.AccessList, nil
+}
+
+// BlobFeeCap returns the max fee per blob gas of blob txs, nil for the other types
+func (tx *LazyTx) BlobFeeCap() (*big.Int, error) {
+	return tx.bigInt(fieldBlobFeeCap)
+}
+
+// BlobHashes returns the blob versioned hashes of blob txs, nil for the other types
+func (tx *LazyTx) BlobHashes() ([]common.Hash, error) {
+	if err := tx.decode(fieldBlobHashes); err != nil {
+		return nil, err
+	}
+	return tx.tx.BlobHashes, nil
+}
+
+// Sidecar returns the sidecar of blob txs in their network form, nil otherwise
+func (tx *LazyTx) Sidecar() (*types.BlobTxSidecar, error) {
+	if err := tx.decode(fieldSidecar); err != nil {
+		return nil, err
+	}
+	return tx.tx.Sidecar, nil
+}
+
+// AuthList returns the authorizations of set code txs, nil for the other types
+func (tx *LazyTx) AuthList() ([]types.SetCodeAuthorization, error) {
+	if err := tx.decode(fieldAuthList); err != nil {
+		return nil, err
+	}
+	return tx.tx.AuthList, nil
+}
+
+// RawSignatureValues returns the V, R and S values of the signature
+func (tx *LazyTx) RawSignatureValues() (v, r, s *big.Int, err error) {
+	for _, field := range []txField{fieldV, fieldR, fieldS} {
+		if err = tx.decode(field); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return tx.tx.V, tx.tx.R, tx.tx.S, nil
+}
+
+// SetSigner sets the signer used to recover the sender of legacy txs, see CustomTx.SetSigner
+func (tx *LazyTx) SetSigner(s *Signer) {
+	tx.tx.SetSigner(s)
+}
+
+// Hash returns the hash of the tx, calculated over the rlp bytes without decoding any field
+func (tx *LazyTx) Hash() common.Hash {
+	return tx.tx.Hash()
+}
+
+// From returns the sender of the tx. Only the signature values are decoded, the unsigned hash is calculated over the
+// rlp bytes.
+func (tx *LazyTx) From() (common.Address, error) {
+	if len(tx.tx.from) == 0 {
+		if _, _, _, err := tx.RawSignatureValues(); err != nil {
+			return common.Address{}, err
+		}
+	}
+	return tx.tx.From()
+}
+
+// ToCustomTx decodes every field of the tx into a CustomTx, keeping the hash, sender and signer already known
+func (tx *LazyTx) ToCustomTx() (*CustomTx, error) {
+	r := reader.NewReader(tx.tx.SignedRlpBytes)
+	r.SetStrict(tx.strict)
+	full, err := DecodeTx(r)
+	if err != nil {
+		return nil, err
+	}
+	full.signedHash = tx.tx.signedHash
+	full.unsignedHash = tx.tx.unsignedHash
+	full.from = tx.tx.from
+	full.signer = tx.tx.signer
+	return full, nil
+}
@@ -0,0 +1,102 @@
+package genTx
+
+import (
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDecodeLazyTxsPacket(t *testing.T) {
+	txs := append(fuzzSeedTxsForTests(t), newSignedBlobTxForTests(t, 7))
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(t, err)
+	customTxs, err := DecodeTxsPacket(reader.NewReader(packet))
+	assert.NoError(t, err)
+	lazyTxs, err := DecodeLazyTxsPacket(reader.NewStrictReader(packet))
+	assert.NoError(t, err)
+	assert.Len(t, lazyTxs, len(txs))
+
+	for i, lazyTx := range lazyTxs {
+		tx := txs[i]
+		// nothing is decoded to get the hash
+		assert.Equal(t, tx.Hash(), lazyTx.Hash())
+		assert.Zero(t, lazyTx.decoded)
+		assert.Equal(t, tx.Type(), lazyTx.Type())
+		assert.Equal(t, customTxs[i].SignedRlpBytes, lazyTx.SignedRlpBytes())
+
+		nonce, err := lazyTx.Nonce()
+		assert.NoError(t, err)
+		assert.Equal(t, tx.Nonce(), nonce)
+		to, err := lazyTx.To()
+		assert.NoError(t, err)
+		assert.Equal(t, tx.To(), to)
+		// only the accessed fields are decoded
+		assert.Equal(t, uint32(1<<fieldNonce|1<<fieldTo), lazyTx.decoded)
+
+		from, err := lazyTx.From()
+		assert.NoError(t, err)
+		expectedFrom, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedFrom, from)
+
+		gasFeeCap, err := lazyTx.GasFeeCap()
+		assert.NoError(t, err)
+		assert.Equal(t, customTxs[i].GasFeeCap, gasFeeCap)
+		gasPrice, err := lazyTx.GasPrice()
+		assert.NoError(t, err)
+		assert.Equal(t, customTxs[i].GasPrice, gasPrice)
+		data, err := lazyTx.Data()
+		assert.NoError(t, err)
+		assert.Equal(t, customTxs[i].Data, data)
+		accessList, err := lazyTx.AccessList()
+		assert.NoError(t, err)
+		assert.Equal(t, customTxs[i].AccessList, accessList)
+		blobHashes, err := lazyTx.BlobHashes()
+		assert.NoError(t, err)
+		assert.Equal(t, customTxs[i].BlobHashes, blobHashes)
+		authList, err := lazyTx.AuthList()
+		assert.NoError(t, err)
+		assert.Equal(t, customTxs[i].AuthList, authList)
+		sidecar, err := lazyTx.Sidecar()
+		assert.NoError(t, err)
+		assert.Equal(t, customTxs[i].Sidecar, sidecar)
+
+		full, err := lazyTx.ToCustomTx()
+		assert.NoError(t, err)
+		assert.Equal(t, exportedFieldsForTests(customTxs[i]), exportedFieldsForTests(full))
+		assert.Equal(t, tx.Hash(), full.Hash())
+		fullFrom, err := full.From()
+		assert.NoError(t, err)
+		assert.Equal(t, expectedFrom, fullFrom)
+	}
+}
+
+func TestDecodeLazyTxErrors(t *testing.T) {
+	txs := fuzzSeedTxsForTests(t)
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(t, err)
+
+	// a truncated packet fails when reading the offsets
+	_, err = DecodeLazyTxsPacket(reader.NewReader(packet[:len(packet)-1]))
+	assert.Error(t, err)
+
+	// a non-canonical field is only detected in strict mode, when it is accessed
+	nonCanonicalNonce := rlpListForTests([]byte{0x82, 0x00, 0x01}, []byte{0x01}, []byte{0x01}, []byte{0x80},
+		[]byte{0x80}, []byte{0x80}, []byte{0x1b}, []byte{0x01}, []byte{0x01})
+	lazyTx, err := DecodeLazyTx(reader.NewStrictReader(nonCanonicalNonce))
+	assert.NoError(t, err)
+	gas, err := lazyTx.Gas()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), gas)
+	_, err = lazyTx.Nonce()
+	assert.ErrorIs(t, err, errors.ErrIntLeadingZero)
+	assert.Equal(t, "nonce", errors.GetPosition(err).Field)
+	assert.Equal(t, uint64(1), errors.GetPosition(err).Offset)
+
+	// missing fields
+	_, err = DecodeLazyTx(reader.NewReader(rlpListForTests([]byte{0x01}, []byte{0x01})))
+	assert.ErrorIs(t, err, errors.ErrUnexpectedLength)
+}
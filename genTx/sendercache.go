@@ -0,0 +1,73 @@
+package genTx
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"sync/atomic"
+)
+
+// SenderCache is a bounded, concurrency safe LRU cache of tx senders keyed by the signed tx hash.
+// The same tx is usually received from many peers, so caching its sender avoids running ecrecover for every copy.
+type SenderCache struct {
+	senders *lru.Cache[common.Hash, common.Address]
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}
+
+// senderCache is the cache used by CustomTx.From. Disabled (nil) by default, see SetSenderCache
+var senderCache atomic.Pointer[SenderCache]
+
+// NewSenderCache creates a new SenderCache that holds up to capacity senders
+func NewSenderCache(capacity int) *SenderCache {
+	return &SenderCache{
+		senders: lru.NewCache[common.Hash, common.Address](capacity),
+	}
+}
+
+// SetSenderCache sets the process wide cache consulted and populated by CustomTx.From. A nil cache disables it.
+func SetSenderCache(c *SenderCache) {
+	senderCache.Store(c)
+}
+
+// GetSenderCache returns the process wide sender cache, nil if it is disabled
+func GetSenderCache() *SenderCache {
+	return senderCache.Load()
+}
+
+// Get returns the cached sender of the tx with the provided hash
+func (c *SenderCache) Get(hash common.Hash) (common.Address, bool) {
+	from, ok := c.senders.Get(hash)
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return from, ok
+}
+
+// Add stores the sender of the tx with the provided hash, evicting the least recently used one if the cache is full
+func (c *SenderCache) Add(hash common.Hash, from common.Address) {
+	c.senders.Add(hash, from)
+}
+
+// Len returns the number of cached senders
+func (c *SenderCache) Len() int {
+	return c.senders.Len()
+}
+
+// Hits returns how many lookups found the sender in the cache
+func (c *SenderCache) Hits() uint64 {
+	return c.hits.Load()
+}
+
+// Misses returns how many lookups did not find the sender in the cache
+func (c *SenderCache) Misses() uint64 {
+	return c.misses.Load()
+}
+
+// Purge removes every cached sender and resets the counters
+func (c *SenderCache) Purge() {
+	c.senders.Purge()
+	c.hits.Store(0)
+	c.misses.Store(0)
+}
@@ -0,0 +1,118 @@
+package genTx
+
+import (
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestSenderCache_LRU(t *testing.T) {
+	cache := NewSenderCache(2)
+	cache.Add(common.Hash{0x01}, common.Address{0x01})
+	cache.Add(common.Hash{0x02}, common.Address{0x02})
+
+	// touch the first one so the second one is the least recently used
+	from, ok := cache.Get(common.Hash{0x01})
+	assert.True(t, ok)
+	assert.Equal(t, common.Address{0x01}, from)
+
+	cache.Add(common.Hash{0x03}, common.Address{0x03})
+	assert.Equal(t, 2, cache.Len())
+	_, ok = cache.Get(common.Hash{0x02})
+	assert.False(t, ok)
+	_, ok = cache.Get(common.Hash{0x03})
+	assert.True(t, ok)
+
+	assert.Equal(t, uint64(2), cache.Hits())
+	assert.Equal(t, uint64(1), cache.Misses())
+
+	cache.Purge()
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, uint64(0), cache.Hits())
+	assert.Equal(t, uint64(0), cache.Misses())
+}
+
+func TestCustomTx_From_SenderCache(t *testing.T) {
+	cache := NewSenderCache(16)
+	SetSenderCache(cache)
+	defer SetSenderCache(nil)
+
+	privKey, err := getPrivkeyForTests()
+	if err != nil {
+		t.Fatalf("Failed to get private key: %v", err)
+	}
+	chainId := big.NewInt(56)
+	tx, err := types.SignNewTx(privKey, types.LatestSignerForChainID(chainId), &types.DynamicFeeTx{
+		ChainID:   chainId,
+		Nonce:     1,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(1),
+		Gas:       21000,
+		To:        &common.Address{0x01},
+	})
+	if err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	rlpBytes, err := rlp.EncodeToBytes(types.Transactions{tx})
+	if err != nil {
+		t.Fatalf("Failed to RLP encode transactions: %v", err)
+	}
+	want := crypto.PubkeyToAddress(privKey.PublicKey)
+
+	// first copy misses and populates the cache
+	first, err := DecodeTxsPacket(reader.NewReader(rlpBytes))
+	if err != nil {
+		t.Fatalf("Failed to decode transactions: %v", err)
+	}
+	from, err := first[0].From()
+	assert.NoError(t, err)
+	assert.Equal(t, want, from)
+	assert.Equal(t, uint64(0), cache.Hits())
+	assert.Equal(t, uint64(1), cache.Misses())
+	assert.Equal(t, 1, cache.Len())
+
+	// second copy must be served from the cache, so breaking its signature values does not matter
+	second, err := DecodeTxsPacket(reader.NewReader(rlpBytes))
+	if err != nil {
+		t.Fatalf("Failed to decode transactions: %v", err)
+	}
+	second[0].R = new(big.Int)
+	from, err = second[0].From()
+	assert.NoError(t, err)
+	assert.Equal(t, want, from)
+	assert.Equal(t, uint64(1), cache.Hits())
+	assert.Equal(t, uint64(1), cache.Misses())
+}
+
+func TestCustomTx_From_SenderCacheFromTx(t *testing.T) {
+	cache := NewSenderCache(64)
+	SetSenderCache(cache)
+	defer SetSenderCache(nil)
+
+	for i, tx := range tx256TxsForTests(t) {
+		want := senderForTests(t, tx)
+
+		// built from the fields, so the hash saves the rlp bytes the sender is recovered from
+		built := new(CustomTx)
+		assert.NoError(t, built.FromTx(tx))
+		from, err := built.From()
+		assert.NoError(t, err, "tx %d", i)
+		assert.Equal(t, want, from, "tx %d", i)
+
+		// a decoded copy of the same tx is served from the cache
+		rlpBytes, err := rlp.EncodeToBytes(types.Transactions{tx})
+		assert.NoError(t, err)
+		decoded, err := DecodeTxsPacket(reader.NewReader(rlpBytes))
+		assert.NoError(t, err)
+		hits := cache.Hits()
+		from, err = decoded[0].From()
+		assert.NoError(t, err, "tx %d", i)
+		assert.Equal(t, want, from, "tx %d", i)
+		assert.Equal(t, hits+1, cache.Hits(), "tx %d", i)
+	}
+}
@@ -0,0 +1,55 @@
+package genTx
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// recoverSendersChunk is the number of txs a worker takes each time from the batch
+const recoverSendersChunk = 16
+
+// RecoverSenders recovers the sender of every tx using up to workers goroutines (GOMAXPROCS when workers <= 0).
+// The sender is cached in each tx, so calling From afterwards does not recover it again.
+// The returned slice has the same length as txs and holds the error of the tx in the same position, if any.
+func RecoverSenders(txs []*CustomTx, workers int) []error {
+	errs := make([]error, len(txs))
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if maxWorkers := (len(txs) + recoverSendersChunk - 1) / recoverSendersChunk; workers > maxWorkers {
+		workers = maxWorkers
+	}
+	if workers <= 1 {
+		for i, tx := range txs {
+			_, errs[i] = tx.From()
+		}
+		return errs
+	}
+
+	var (
+		next int64
+		wg   sync.WaitGroup
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				end := int(atomic.AddInt64(&next, recoverSendersChunk))
+				start := end - recoverSendersChunk
+				if start >= len(txs) {
+					return
+				}
+				if end > len(txs) {
+					end = len(txs)
+				}
+				for i := start; i < end; i++ {
+					_, errs[i] = txs[i].From()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return errs
+}
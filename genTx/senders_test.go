@@ -0,0 +1,72 @@
+package genTx
+
+import (
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestRecoverSenders(t *testing.T) {
+	chainId := big.NewInt(56)
+	signer := types.LatestSignerForChainID(chainId)
+
+	var (
+		txs   types.Transactions
+		froms []common.Address
+	)
+	for i := 0; i < 100; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatalf("Failed to generate key: %v", err)
+		}
+		var txData types.TxData
+		switch i % 3 {
+		case 0:
+			txData = &types.LegacyTx{Nonce: uint64(i), GasPrice: big.NewInt(1), Gas: 21000, To: &common.Address{0x01}}
+		case 1:
+			txData = &types.AccessListTx{ChainID: chainId, Nonce: uint64(i), GasPrice: big.NewInt(1), Gas: 21000, To: &common.Address{0x01}}
+		default:
+			txData = &types.DynamicFeeTx{ChainID: chainId, Nonce: uint64(i), GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1), Gas: 21000}
+		}
+		tx, err := types.SignNewTx(key, signer, txData)
+		if err != nil {
+			t.Fatalf("Failed to sign transaction: %v", err)
+		}
+		txs = append(txs, tx)
+		froms = append(froms, crypto.PubkeyToAddress(key.PublicKey))
+	}
+
+	rlpBytes, err := rlp.EncodeToBytes(txs)
+	if err != nil {
+		t.Fatalf("Failed to RLP encode transactions: %v", err)
+	}
+
+	for _, workers := range []int{0, 1, 3, 1000} {
+		customTxs, err := DecodeTxsPacket(reader.NewReader(rlpBytes))
+		if err != nil {
+			t.Fatalf("Failed to decode transactions: %v", err)
+		}
+		// an invalid signature must only fail its own tx
+		invalid := 42
+		customTxs[invalid].R = new(big.Int)
+
+		errs := RecoverSenders(customTxs, workers)
+		assert.Len(t, errs, len(customTxs))
+		for i, tx := range customTxs {
+			if i == invalid {
+				assert.ErrorIs(t, errs[i], errors.ErrInvalidSig, "workers %d pos %d", workers, i)
+				continue
+			}
+			assert.NoError(t, errs[i], "workers %d pos %d", workers, i)
+			// the sender must be cached
+			assert.Equal(t, froms[i], common.BytesToAddress(tx.from), "workers %d pos %d", workers, i)
+		}
+	}
+	assert.Len(t, RecoverSenders(nil, 0), 0)
+}
@@ -0,0 +1,138 @@
+package genTx
+
+import (
+	"bytes"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/holiman/uint256"
+	"math/big"
+	"sync/atomic"
+)
+
+// Signer holds the chain dependent values needed to sign legacy txs and recover their sender (EIP-155).
+// A Signer without chain id is used for unprotected (pre EIP-155) legacy txs signed with V 27 or 28.
+// A Signer is immutable, so the same value can be shared by txs of the same chain across goroutines.
+type Signer struct {
+	chainId     *big.Int
+	vMultiplier *big.Int // chainId * 2
+	// vMultiplier256 is vMultiplier as used by Tx256, chain ids that do not fit in 255 bits are not supported by it
+	vMultiplier256 uint256.Int
+	signerValues   []byte // rlp of [chainId, 0, 0] appended to the unsigned legacy tx
+}
+
+// maxCachedSigners is the number of chain ids whose Signer is cached
+const maxCachedSigners = 64
+
+// signers caches the Signers of the chain ids that fit in a uint64 and were seen last, so deriving it from a tx does
+// not allocate. It is bounded since the chain ids come from the V values and chain ids of the txs peers send.
+var signers = lru.NewCache[uint64, *Signer](maxCachedSigners)
+
+// unprotectedSigner is used for legacy txs without replay protection
+var unprotectedSigner = &Signer{}
+
+// defaultSigner is used by txs that have neither a signer, a signature nor a chain id. It is set by Init.
+var defaultSigner atomic.Pointer[Signer]
+
+func init() {
+	defaultSigner.Store(NewSigner(big.NewInt(1)))
+}
+
+// DefaultSigner returns the signer set by Init, the one of chain 1 until then
+func DefaultSigner() *Signer {
+	return defaultSigner.Load()
+}
+
+// NewSigner creates a new Signer for the provided chain id
+func NewSigner(chainId *big.Int) *Signer {
+	s := &Signer{
+		chainId:     new(big.Int).Set(chainId),
+		vMultiplier: new(big.Int).Lsh(chainId, 1),
+	}
+	s.vMultiplier256.SetFromBig(s.vMultiplier)
+	buf := bytes.NewBuffer(make([]byte, 0, 12))
+	_ = WriteRLPBytes(buf, s.chainId.Bytes())
+	buf.WriteByte(ZeroUint64RLPVal)
+	buf.WriteByte(ZeroUint64RLPVal)
+	s.signerValues = buf.Bytes()
+	return s
+}
+
+// NewUnprotectedSigner returns the signer of legacy txs without replay protection (pre EIP-155).
+// The unsigned tx has no chain id suffix and V is 27 or 28.
+func NewUnprotectedSigner() *Signer {
+	return unprotectedSigner
+}
+
+// SignerForChainID returns a Signer for the provided chain id, cached if the chain id is one of the last ones used
+func SignerForChainID(chainId *big.Int) *Signer {
+	if !chainId.IsUint64() {
+		return NewSigner(chainId)
+	}
+	return signerForUint64(chainId.Uint64())
+}
+
+// signerForUint64 returns the cached Signer of the chain id, allocating only when the chain id is not cached
+func signerForUint64(chainId uint64) *Signer {
+	if s, ok := signers.Get(chainId); ok {
+		return s
+	}
+	s := NewSigner(new(big.Int).SetUint64(chainId))
+	signers.Add(chainId, s)
+	return s
+}
+
+// signerFromV derives the signer of an EIP-155 legacy tx from its V value: chainId = (V - 35) / 2
+func signerFromV(v *big.Int) *Signer {
+	if v.IsUint64() {
+		return signerForUint64((v.Uint64() - 35) / 2)
+	}
+	chainId := new(big.Int).Sub(v, big.NewInt(35))
+	return SignerForChainID(chainId.Rsh(chainId, 1))
+}
+
+// ChainID returns the chain id of the signer, nil when the signer is unprotected
+func (s *Signer) ChainID() *big.Int {
+	if s.chainId == nil {
+		return nil
+	}
+	return new(big.Int).Set(s.chainId)
+}
+
+// Protected returns whether the signer uses EIP-155 replay protection
+func (s *Signer) Protected() bool {
+	return s.chainId != nil
+}
+
+// isUnprotectedV checks whether V is the one of a legacy tx without replay protection
+func isUnprotectedV(v *big.Int) bool {
+	if v.BitLen() > 8 {
+		return false
+	}
+	b := v.Uint64()
+	return b == 27 || b == 28
+}
+
+// SetSigner sets the signer used when signing the tx, recovering its sender and doing the unsigned legacy encoding
+func (tx *CustomTx) SetSigner(s *Signer) {
+	tx.signer = s
+}
+
+// Signer returns the signer of the tx. When none has been set it is derived from the tx itself:
+// from V for signed legacy txs (V 27 or 28 meaning no replay protection), then from ChainID, falling back to the
+// signer set by Init.
+func (tx *CustomTx) Signer() *Signer {
+	if tx.signer != nil {
+		return tx.signer
+	}
+	if tx.TxType == 0 && tx.V != nil {
+		if isUnprotectedV(tx.V) {
+			return unprotectedSigner
+		}
+		if tx.V.Cmp(big35) >= 0 {
+			return signerFromV(tx.V)
+		}
+	}
+	if tx.ChainID != nil && tx.ChainID.Sign() > 0 {
+		return SignerForChainID(tx.ChainID)
+	}
+	return defaultSigner.Load()
+}
@@ -0,0 +1,232 @@
+package genTx
+
+import (
+	"bytes"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func TestSigner_MultipleChainsLegacyTxs(t *testing.T) {
+	privKey, err := getPrivkeyForTests()
+	if err != nil {
+		t.Fatalf("Failed to get private key: %v", err)
+	}
+	// the global chain id must not be used by any of the txs below
+	Init(big.NewInt(97))
+
+	chainIds := []*big.Int{big.NewInt(1), big.NewInt(56), big.NewInt(6969), big.NewInt(8453)}
+	var wg sync.WaitGroup
+	for _, chainId := range chainIds {
+		wg.Add(1)
+		go func(chainId *big.Int) {
+			defer wg.Done()
+			signer := types.LatestSignerForChainID(chainId)
+			want, err := types.SignNewTx(privKey, signer, &types.LegacyTx{
+				Nonce:    1,
+				GasPrice: big.NewInt(1000000000),
+				Gas:      21000,
+				To:       &common.Address{0x01},
+				Value:    big.NewInt(1),
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			wantFrom, err := types.Sender(signer, want)
+			assert.NoError(t, err)
+
+			// signing with a signer set on the tx
+			customTx := new(CustomTx)
+			assert.NoError(t, customTx.FromTx(want))
+			customTx.ResetSignedVals()
+			customTx.SetSigner(NewSigner(chainId))
+			assert.NoError(t, customTx.SignTx(privKey))
+			v, r, s := want.RawSignatureValues()
+			assert.Equal(t, v, customTx.V, "chain %d v mismatch", chainId)
+			assert.Equal(t, r, customTx.R, "chain %d r mismatch", chainId)
+			assert.Equal(t, s, customTx.S, "chain %d s mismatch", chainId)
+			assert.Equal(t, want.Hash(), customTx.Hash(), "chain %d hash mismatch", chainId)
+
+			// recovering the sender of a decoded tx, the chain id is taken from V
+			rlpBytes, err := rlp.EncodeToBytes(want)
+			assert.NoError(t, err)
+			decoded, err := DecodeLegacyTx(reader.NewReader(rlpBytes))
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, chainId, decoded.Signer().ChainID())
+			from, err := decoded.From()
+			assert.NoError(t, err)
+			assert.Equal(t, wantFrom, from, "chain %d from mismatch", chainId)
+		}(chainId)
+	}
+	wg.Wait()
+}
+
+func TestSigner_UnprotectedLegacyTx(t *testing.T) {
+	privKey, err := getPrivkeyForTests()
+	if err != nil {
+		t.Fatalf("Failed to get private key: %v", err)
+	}
+	Init(big.NewInt(56))
+
+	signer := types.HomesteadSigner{}
+	txData := &types.LegacyTx{
+		Nonce:    7,
+		GasPrice: big.NewInt(1000000000),
+		Gas:      21000,
+		To:       &common.Address{0x01},
+		Value:    big.NewInt(1),
+		Data:     []byte{0xaa, 0xbb},
+	}
+	want, err := types.SignNewTx(privKey, signer, txData)
+	if err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	assert.False(t, want.Protected())
+	wantFrom, err := types.Sender(signer, want)
+	assert.NoError(t, err)
+
+	// recovering the sender of a decoded unprotected tx
+	rlpBytes, err := rlp.EncodeToBytes(want)
+	assert.NoError(t, err)
+	decoded, err := DecodeLegacyTx(reader.NewReader(rlpBytes))
+	if err != nil {
+		t.Fatalf("Failed to decode transaction: %v", err)
+	}
+	assert.False(t, decoded.Signer().Protected())
+	assert.Nil(t, decoded.Signer().ChainID())
+	from, err := decoded.From()
+	assert.NoError(t, err)
+	assert.Equal(t, wantFrom, from)
+	assert.Equal(t, signer.Hash(want), decoded.UnsignedHash())
+
+	// same but encoding the unsigned tx from its values
+	decoded, err = DecodeLegacyTx(reader.NewReader(rlpBytes))
+	if err != nil {
+		t.Fatalf("Failed to decode transaction: %v", err)
+	}
+	decoded.SignedRlpBytes = []byte{}
+	from, err = decoded.From()
+	assert.NoError(t, err)
+	assert.Equal(t, wantFrom, from)
+
+	// signing an unprotected tx
+	customTx := &CustomTx{
+		TxType:   types.LegacyTxType,
+		Nonce:    txData.Nonce,
+		GasPrice: txData.GasPrice,
+		Gas:      txData.Gas,
+		To:       txData.To,
+		Value:    txData.Value,
+		Data:     txData.Data,
+	}
+	customTx.SetSigner(NewUnprotectedSigner())
+	assert.NoError(t, customTx.SignTx(privKey))
+	v, r, s := want.RawSignatureValues()
+	assert.Equal(t, v, customTx.V)
+	assert.Equal(t, r, customTx.R)
+	assert.Equal(t, s, customTx.S)
+	assert.Equal(t, want.Hash(), customTx.Hash())
+
+	buf := bytes.NewBuffer([]byte{})
+	assert.NoError(t, customTx.EncodeSignedRLP(buf, false))
+	assert.Equal(t, rlpBytes, buf.Bytes())
+}
+
+func TestSigner_WrongChainLegacyTx(t *testing.T) {
+	privKey, err := getPrivkeyForTests()
+	if err != nil {
+		t.Fatalf("Failed to get private key: %v", err)
+	}
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	// a tx of each recovery id, V 37 and 38 for chain 1
+	var txs []*types.Transaction
+	for nonce, seen := uint64(0), map[uint64]bool{}; len(seen) < 2; nonce++ {
+		tx, err := types.SignNewTx(privKey, signer, &types.LegacyTx{Nonce: nonce, GasPrice: big.NewInt(1), Gas: 21000})
+		assert.NoError(t, err)
+		v, _, _ := tx.RawSignatureValues()
+		if !seen[v.Uint64()] {
+			seen[v.Uint64()] = true
+			txs = append(txs, tx)
+		}
+	}
+	for _, want := range txs {
+		v, _, _ := want.RawSignatureValues()
+		rlpBytes, err := rlp.EncodeToBytes(want)
+		assert.NoError(t, err)
+		// V is below, just below and above the values of the checked chain
+		for _, chainId := range []int64{2, 3, 0} {
+			decoded, err := DecodeLegacyTx(reader.NewReader(rlpBytes))
+			assert.NoError(t, err)
+			tx256, err := DecodeTx256(reader.NewReader(rlpBytes))
+			assert.NoError(t, err)
+			if chainId == 0 {
+				decoded.SetSigner(NewUnprotectedSigner())
+				tx256.SetSigner(NewUnprotectedSigner())
+			} else {
+				decoded.SetSigner(NewSigner(big.NewInt(chainId)))
+				tx256.SetSigner(NewSigner(big.NewInt(chainId)))
+			}
+			_, err = decoded.From()
+			assert.ErrorIs(t, err, errors.ErrInvalidSig, "V %d checked for chain %d", v, chainId)
+			_, err = tx256.From()
+			assert.ErrorIs(t, err, errors.ErrInvalidSig, "V %d checked for chain %d", v, chainId)
+		}
+	}
+
+	// unprotected V values wrapping around a byte
+	unprotected, err := types.SignNewTx(privKey, types.HomesteadSigner{}, &types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000})
+	assert.NoError(t, err)
+	customTx := new(CustomTx)
+	assert.NoError(t, customTx.FromTx(unprotected))
+	customTx.SetSigner(NewUnprotectedSigner())
+	customTx.V = new(big.Int).Add(customTx.V, big.NewInt(256))
+	_, err = customTx.From()
+	assert.ErrorIs(t, err, errors.ErrInvalidSig)
+}
+
+func TestSigner_CacheIsBounded(t *testing.T) {
+	// legacy txs with arbitrary V values, as a peer can send them
+	for v := int64(35); v < 35+8*maxCachedSigners; v += 2 {
+		tx := &CustomTx{V: big.NewInt(v)}
+		signer := tx.Signer()
+		assert.Equal(t, big.NewInt((v-35)/2), signer.ChainID())
+		assert.LessOrEqual(t, signers.Len(), maxCachedSigners)
+	}
+	// the chain id used last is still cached, the first one has been evicted
+	_, ok := signers.Get(4*maxCachedSigners - 1)
+	assert.True(t, ok)
+	_, ok = signers.Get(0)
+	assert.False(t, ok)
+}
+
+func TestInit_Concurrent(t *testing.T) {
+	defer Init(big.NewInt(1))
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := int64(1); i <= 100; i++ {
+			Init(big.NewInt(i))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		tx := &CustomTx{TxType: types.LegacyTxType}
+		tx256 := &Tx256{TxType: types.LegacyTxType}
+		for i := 0; i < 100; i++ {
+			// txs without signer, signature nor chain id use the signer set by Init
+			assert.True(t, tx.Signer().Protected())
+			assert.True(t, tx256.Signer().Protected())
+		}
+	}()
+	wg.Wait()
+	assert.Equal(t, big.NewInt(100), DefaultSigner().ChainID())
+}
@@ -0,0 +1,96 @@
+package genTx
+
+import (
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+)
+
+// DecodePoolTxsPacketStream is the same as DecodePoolTxsPacket but reading the packet from a StreamReader
+func DecodePoolTxsPacketStream(s *reader.StreamReader) ([]*CustomTx, error) {
+	// read list length
+	_, err := s.ReadListSize()
+	if err != nil {
+		return []*CustomTx{}, err
+	}
+	_, err = s.DecodeUint64()
+	if err != nil {
+		return []*CustomTx{}, errors.WithField(err, "requestId")
+	}
+	return DecodeTxsPacketStream(s)
+}
+
+// DecodeTxsPacketStream is the same as DecodeTxsPacket but reading the packet from a StreamReader.
+// Every tx is read from the stream on its own and decoded with the same decoders used by DecodeTxsPacket, so the tx
+// bytes are not copied when the tx fits in the stream window. The txs are decoded in strict mode when the stream is.
+func DecodeTxsPacketStream(s *reader.StreamReader) ([]*CustomTx, error) {
+	var txs []*CustomTx
+	// read list length
+	listSize, err := s.ReadListSize()
+	if err != nil {
+		return nil, err
+	}
+	cPos := s.Pos()
+	for i := 0; s.Pos()-cPos < listSize; i++ {
+		start := s.Pos()
+		rlpBytes, err := s.ReadRaw()
+		if err != nil {
+			return txs, errors.WithTxIndex(err, i)
+		}
+		r := reader.NewReader(rlpBytes)
+		r.SetStrict(s.Strict())
+		tx, err := DecodeTx(r)
+		if err != nil {
+			if errors.Is(err, errors.ErrTxTypeNotSupported) {
+				continue
+			}
+			// the offsets of the tx reader are relative to the tx
+			return txs, errors.WithTxIndex(errors.ShiftOffset(err, start), i)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// DecodePoolTxsPacketStreamInto is the same as DecodePoolTxsPacketStream but decoding into the txs of dst, see
+// DecodeTxsPacketInto
+func DecodePoolTxsPacketStreamInto(s *reader.StreamReader, dst []*CustomTx) ([]*CustomTx, error) {
+	_, err := s.ReadListSize()
+	if err != nil {
+		return dst[:0], err
+	}
+	_, err = s.DecodeUint64()
+	if err != nil {
+		return dst[:0], errors.WithField(err, "requestId")
+	}
+	return DecodeTxsPacketStreamInto(s, dst)
+}
+
+// DecodeTxsPacketStreamInto is the same as DecodeTxsPacketStream but decoding into the txs of dst, see
+// DecodeTxsPacketInto.
+func DecodeTxsPacketStreamInto(s *reader.StreamReader, dst []*CustomTx) ([]*CustomTx, error) {
+	txs := dst[:0]
+	listSize, err := s.ReadListSize()
+	if err != nil {
+		return txs, err
+	}
+	var r reader.RlpReader
+	r.SetStrict(s.Strict())
+	cPos := s.Pos()
+	for i := 0; s.Pos()-cPos < listSize; i++ {
+		start := s.Pos()
+		rlpBytes, err := s.ReadRaw()
+		if err != nil {
+			return txs, errors.WithTxIndex(err, i)
+		}
+		r.Reset(rlpBytes)
+		tx := nextReusableTx(txs)
+		if err = DecodeTxInto(&r, tx); err != nil {
+			if errors.Is(err, errors.ErrTxTypeNotSupported) {
+				continue
+			}
+			return txs, errors.WithTxIndex(errors.ShiftOffset(err, start), i)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
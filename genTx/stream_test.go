@@ -0,0 +1,121 @@
+package genTx
+
+import (
+	"bytes"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"math/big"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDecodePoolTxsPacketStream(t *testing.T) {
+	privKey, err := getPrivkeyForTests()
+	if err != nil {
+		t.Fatalf("Failed to get private key: %v", err)
+	}
+	chainId := big.NewInt(6969)
+	signer := types.LatestSignerForChainID(chainId)
+	var txs types.Transactions
+	for i := 0; i < 20; i++ {
+		tx, err := types.SignNewTx(privKey, signer, &types.DynamicFeeTx{
+			ChainID:   chainId,
+			Nonce:     uint64(i),
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(2),
+			Gas:       21000,
+			To:        &common.Address{0x01},
+			Data:      bytes.Repeat([]byte{0xfa}, i*10),
+		})
+		if err != nil {
+			t.Fatalf("Failed to sign transaction: %v", err)
+		}
+		txs = append(txs, tx)
+	}
+	// the blob tx does not fit in any of the windows below
+	txs = append(txs, newSignedBlobTxForTests(t, 100))
+
+	rlpBytes, err := rlp.EncodeToBytes(eth.PooledTransactionsPacket{
+		RequestId:                  1,
+		PooledTransactionsResponse: eth.PooledTransactionsResponse(txs),
+	})
+	if err != nil {
+		t.Fatalf("Failed to RLP encode transactions: %v", err)
+	}
+
+	tests := []struct {
+		Name   string
+		Src    io.Reader
+		Window int
+	}{
+		{Name: "Test default window", Src: bytes.NewReader(rlpBytes)},
+		{Name: "Test small window", Src: bytes.NewReader(rlpBytes), Window: 512},
+		{Name: "Test one byte reads", Src: iotest.OneByteReader(bytes.NewReader(rlpBytes)), Window: 256},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			s := reader.NewStreamReader(tt.Src, tt.Window)
+			customTxs, err := DecodePoolTxsPacketStream(s)
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(len(rlpBytes)), s.Pos(), "not all data consumed")
+			assert.Len(t, customTxs, len(txs))
+			for i, want := range txs {
+				got := customTxs[i]
+				assert.Equal(t, want.Hash(), got.Hash(), "pos %d hash mismatch", i)
+				wantRLP, err := rlp.EncodeToBytes(want)
+				assert.NoError(t, err)
+				assert.Equal(t, wantRLP, got.SignedRlpBytes, "pos %d rlp mismatch", i)
+				from, err := got.From()
+				assert.NoError(t, err)
+				wantFrom, _ := types.Sender(signer, want)
+				assert.Equal(t, wantFrom, from, "pos %d from mismatch", i)
+			}
+		})
+	}
+
+	t.Run("Test truncated stream", func(t *testing.T) {
+		s := reader.NewStreamReader(bytes.NewReader(rlpBytes[:len(rlpBytes)/2]), 512)
+		_, err := DecodePoolTxsPacketStream(s)
+		assert.ErrorIs(t, err, io.EOF)
+	})
+}
+
+func TestDecodeTxsPacketStreamStrict(t *testing.T) {
+	to := append([]byte{0x94}, common.HexToAddress("0x2f2a2543b76a4166549f7aab2e75bef0aefc5b0f").Bytes()...)
+	sig := append([]byte{0xa0}, bytes.Repeat([]byte{0x11}, 32)...)
+	// nonce with a leading zero byte
+	tx := rlpListForTests([]byte{0x82, 0x00, 0x05}, []byte{0x80}, []byte{0x82, 0x52, 0x08}, to, []byte{0x80}, []byte{0x80},
+		[]byte{0x25}, sig, sig)
+	packet := rlpListForTests(tx)
+
+	txs, err := DecodeTxsPacketStream(reader.NewStreamReader(bytes.NewReader(packet), 0))
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+
+	s := reader.NewStreamReader(bytes.NewReader(packet), 0)
+	s.SetStrict(true)
+	_, err = DecodeTxsPacketStream(s)
+	assert.True(t, errors.Is(err, errors.ErrIntLeadingZero), "unexpected error %v", err)
+}
+
+func TestDecodePoolTxsPacketStreamInto(t *testing.T) {
+	txs := append(fuzzSeedTxsForTests(t), newSignedBlobTxForTests(t, 7))
+	packet, err := rlp.EncodeToBytes([]interface{}{uint64(9), txs})
+	assert.NoError(t, err)
+
+	var dst []*CustomTx
+	for i := 0; i < 2; i++ {
+		dst, err = DecodePoolTxsPacketStreamInto(reader.NewStreamReader(iotest.HalfReader(bytes.NewReader(packet)), 64), dst)
+		assert.NoError(t, err)
+		assert.Len(t, dst, len(txs))
+		for j, tx := range dst {
+			assert.Equal(t, txs[j].Hash(), tx.Hash())
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package genTx
+
+import (
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+	"math/big"
+)
+
+// ToTx converts the tx into a go-ethereum transaction, keeping its signature.
+// When the tx has its SignedRlpBytes they are decoded directly instead of encoding the values again.
+func (tx *CustomTx) ToTx() (*types.Transaction, error) {
+	if len(tx.SignedRlpBytes) > 0 {
+		normalTx := new(types.Transaction)
+		err := rlp.DecodeBytes(tx.SignedRlpBytes, normalTx)
+		if err != nil {
+			return nil, err
+		}
+		return normalTx, nil
+	}
+
+	var txData types.TxData
+	switch tx.TxType {
+	case types.LegacyTxType:
+		txData = &types.LegacyTx{
+			Nonce:    tx.Nonce,
+			GasPrice: bigOrZero(tx.GasPrice),
+			Gas:      tx.Gas,
+			To:       tx.To,
+			Value:    bigOrZero(tx.Value),
+			Data:     tx.Data,
+			V:        bigOrZero(tx.V),
+			R:        bigOrZero(tx.R),
+			S:        bigOrZero(tx.S),
+		}
+	case types.AccessListTxType:
+		txData = &types.AccessListTx{
+			ChainID:    bigOrZero(tx.ChainID),
+			Nonce:      tx.Nonce,
+			GasPrice:   bigOrZero(tx.GasPrice),
+			Gas:        tx.Gas,
+			To:         tx.To,
+			Value:      bigOrZero(tx.Value),
+			Data:       tx.Data,
+			AccessList: tx.AccessList,
+			V:          bigOrZero(tx.V),
+			R:          bigOrZero(tx.R),
+			S:          bigOrZero(tx.S),
+		}
+	case types.DynamicFeeTxType:
+		txData = &types.DynamicFeeTx{
+			ChainID:    bigOrZero(tx.ChainID),
+			Nonce:      tx.Nonce,
+			GasTipCap:  bigOrZero(tx.GasTipCap),
+			GasFeeCap:  bigOrZero(tx.GasFeeCap),
+			Gas:        tx.Gas,
+			To:         tx.To,
+			Value:      bigOrZero(tx.Value),
+			Data:       tx.Data,
+			AccessList: tx.AccessList,
+			V:          bigOrZero(tx.V),
+			R:          bigOrZero(tx.R),
+			S:          bigOrZero(tx.S),
+		}
+	case types.BlobTxType:
+		if tx.To == nil {
+			return nil, errors.ErrValueNotSupport.WithMessage("blob tx without to")
+		}
+		vals, err := uint256Values(tx.ChainID, tx.GasTipCap, tx.GasFeeCap, tx.Value, tx.BlobFeeCap, tx.V, tx.R, tx.S)
+		if err != nil {
+			return nil, err
+		}
+		txData = &types.BlobTx{
+			ChainID:    vals[0],
+			Nonce:      tx.Nonce,
+			GasTipCap:  vals[1],
+			GasFeeCap:  vals[2],
+			Gas:        tx.Gas,
+			To:         *tx.To,
+			Value:      vals[3],
+			Data:       tx.Data,
+			AccessList: tx.AccessList,
+			BlobFeeCap: vals[4],
+			BlobHashes: tx.BlobHashes,
+			Sidecar:    tx.Sidecar,
+			V:          vals[5],
+			R:          vals[6],
+			S:          vals[7],
+		}
+	case types.SetCodeTxType:
+		if tx.To == nil {
+			return nil, errors.ErrValueNotSupport.WithMessage("set code tx without to")
+		}
+		vals, err := uint256Values(tx.ChainID, tx.GasTipCap, tx.GasFeeCap, tx.Value, tx.V, tx.R, tx.S)
+		if err != nil {
+			return nil, err
+		}
+		txData = &types.SetCodeTx{
+			ChainID:    vals[0],
+			Nonce:      tx.Nonce,
+			GasTipCap:  vals[1],
+			GasFeeCap:  vals[2],
+			Gas:        tx.Gas,
+			To:         *tx.To,
+			Value:      vals[3],
+			Data:       tx.Data,
+			AccessList: tx.AccessList,
+			AuthList:   tx.AuthList,
+			V:          vals[4],
+			R:          vals[5],
+			S:          vals[6],
+		}
+	default:
+		return nil, errors.ErrTxTypeNotSupported
+	}
+	return types.NewTx(txData), nil
+}
+
+// bigOrZero returns the provided value or a new zero value if it is nil
+func bigOrZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
+
+// uint256Values converts the provided values into uint256, nil values are converted into zero
+func uint256Values(vals ...*big.Int) ([]*uint256.Int, error) {
+	res := make([]*uint256.Int, len(vals))
+	for i, v := range vals {
+		res[i] = new(uint256.Int)
+		if v != nil && res[i].SetFromBig(v) {
+			return nil, errors.ErrValueNotSupport.WithMessagef("value %s overflows 256 bits", v)
+		}
+	}
+	return res, nil
+}
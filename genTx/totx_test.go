@@ -0,0 +1,120 @@
+package genTx
+
+import (
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestCustomTx_ToTx(t *testing.T) {
+	privKey, err := getPrivkeyForTests()
+	if err != nil {
+		t.Fatalf("Failed to get private key: %v", err)
+	}
+	chainId := big.NewInt(6969)
+	signer := types.LatestSignerForChainID(chainId)
+	auth, err := types.SignSetCode(privKey, types.SetCodeAuthorization{
+		ChainID: *uint256.MustFromBig(chainId),
+		Address: common.Address{0xde, 0xad},
+		Nonce:   1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to sign authorization: %v", err)
+	}
+
+	txsData := []types.TxData{
+		&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1000), Gas: 21000, To: &common.Address{0x01}, Value: big.NewInt(1)},
+		&types.LegacyTx{Nonce: 2, GasPrice: big.NewInt(1000), Gas: 21000, Data: []byte{0x01, 0x02}},
+		&types.AccessListTx{
+			ChainID:  chainId,
+			Nonce:    3,
+			GasPrice: big.NewInt(1000),
+			Gas:      21000,
+			To:       &common.Address{0x01},
+			AccessList: types.AccessList{
+				{Address: common.Address{0x02}, StorageKeys: []common.Hash{{0x03}}},
+			},
+		},
+		&types.DynamicFeeTx{ChainID: chainId, Nonce: 4, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Gas: 21000, Value: big.NewInt(5)},
+		&types.SetCodeTx{
+			ChainID:   uint256.MustFromBig(chainId),
+			Nonce:     5,
+			GasTipCap: uint256.NewInt(1),
+			GasFeeCap: uint256.NewInt(2),
+			Gas:       100000,
+			To:        common.Address{0x01},
+			Value:     uint256.NewInt(0),
+			AuthList:  []types.SetCodeAuthorization{auth},
+		},
+	}
+	var txs types.Transactions
+	for _, txData := range txsData {
+		tx, err := types.SignNewTx(privKey, signer, txData)
+		if err != nil {
+			t.Fatalf("Failed to sign transaction: %v", err)
+		}
+		txs = append(txs, tx)
+	}
+	txs = append(txs, newSignedBlobTxForTests(t, 6), newSignedBlobTxForTests(t, 7).WithoutBlobTxSidecar())
+
+	checkTx := func(t *testing.T, want, got *types.Transaction) {
+		assert.Equal(t, want.Type(), got.Type())
+		assert.Equal(t, want.Hash(), got.Hash())
+		wantRLP, err := rlp.EncodeToBytes(want)
+		assert.NoError(t, err)
+		gotRLP, err := rlp.EncodeToBytes(got)
+		assert.NoError(t, err)
+		assert.Equal(t, wantRLP, gotRLP)
+		assert.Equal(t, want.BlobTxSidecar(), got.BlobTxSidecar())
+		wantFrom, err := types.Sender(signer, want)
+		assert.NoError(t, err)
+		gotFrom, err := types.Sender(signer, got)
+		assert.NoError(t, err)
+		assert.Equal(t, wantFrom, gotFrom)
+	}
+
+	t.Run("Test txs built from their values", func(t *testing.T) {
+		for _, want := range txs {
+			customTx := new(CustomTx)
+			assert.NoError(t, customTx.FromTx(want))
+			got, err := customTx.ToTx()
+			if !assert.NoError(t, err) {
+				continue
+			}
+			checkTx(t, want, got)
+		}
+	})
+
+	t.Run("Test txs decoded from the wire", func(t *testing.T) {
+		rlpBytes, err := rlp.EncodeToBytes(eth.PooledTransactionsPacket{
+			RequestId:                  1,
+			PooledTransactionsResponse: eth.PooledTransactionsResponse(txs),
+		})
+		if err != nil {
+			t.Fatalf("Failed to RLP encode transactions: %v", err)
+		}
+		customTxs, err := DecodePoolTxsPacket(reader.NewReader(rlpBytes))
+		if err != nil {
+			t.Fatalf("Failed to decode transactions: %v", err)
+		}
+		assert.Len(t, customTxs, len(txs))
+		for i, want := range txs {
+			got, err := customTxs[i].ToTx()
+			if !assert.NoError(t, err) {
+				continue
+			}
+			checkTx(t, want, got)
+		}
+	})
+
+	t.Run("Test blob tx without to", func(t *testing.T) {
+		_, err := (&CustomTx{TxType: types.BlobTxType}).ToTx()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,202 @@
+package genTx
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"io"
+	"strings"
+)
+
+// MaxRLPTreeDepth is the maximum nesting of lists decoded by DecodeRLPNode
+const MaxRLPTreeDepth = 1024
+
+// RLPNode is a value of an RLP tree. Payload points to the decoded bytes, so the node is only valid while they are.
+type RLPNode struct {
+	Kind         RLPValue
+	Offset       uint64     // offset of the value, including its header, in the decoded bytes
+	HeaderLength uint64     // 0 for single bytes < 0x80, which are their own encoding
+	Payload      []byte     // bytes of the value without the header. For lists the encoding of its items
+	Children     []*RLPNode // items of the list, nil for strings
+}
+
+// Len returns the length of the value including its header
+func (n *RLPNode) Len() uint64 {
+	return n.HeaderLength + uint64(len(n.Payload))
+}
+
+// DecodeRLPTree decodes every value left in the reader into a tree. The nodes decoded before an error are returned
+// together with the error.
+func DecodeRLPTree(r *reader.RlpReader) ([]*RLPNode, error) {
+	var nodes []*RLPNode
+	for r.Len() > 0 {
+		node, err := DecodeRLPNode(r)
+		if err != nil {
+			return nodes, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// DecodeRLPNode decodes the next value of the reader, with all its items when it is a list
+func DecodeRLPNode(r *reader.RlpReader) (*RLPNode, error) {
+	return decodeRLPNode(r, 0)
+}
+
+func decodeRLPNode(r *reader.RlpReader, depth int) (*RLPNode, error) {
+	start := r.Pos()
+	if !r.IsNextValAList() {
+		payload, err := r.DecodeNextValue()
+		if err != nil {
+			return nil, err
+		}
+		return &RLPNode{
+			Kind:         RlpString,
+			Offset:       start,
+			HeaderLength: r.Pos() - start - uint64(len(payload)),
+			Payload:      payload,
+		}, nil
+	}
+	if depth >= MaxRLPTreeDepth {
+		return nil, errors.AtOffset(errors.ErrValueNotSupport.WithMessagef("lists nested deeper than %d", MaxRLPTreeDepth), start)
+	}
+	size, err := r.ReadListSize()
+	if err != nil {
+		return nil, err
+	}
+	if !r.EnoughBytes(size) {
+		return nil, errors.AtOffset(io.EOF, start)
+	}
+	node := &RLPNode{
+		Kind:         RlpList,
+		Offset:       start,
+		HeaderLength: r.Pos() - start,
+		Payload:      r.GetBytes(r.Pos(), r.Pos()+size),
+		Children:     make([]*RLPNode, 0),
+	}
+	end := r.Pos() + size
+	for i := 0; r.Pos() < end; i++ {
+		child, err := decodeRLPNode(r, depth+1)
+		if err != nil {
+			return nil, errors.WithField(err, fmt.Sprintf("[%d]", i))
+		}
+		node.Children = append(node.Children, child)
+	}
+	if r.Pos() != end {
+		err = errors.ErrUnexpectedLength.WithMessagef("list items end at %d instead of %d", r.Pos(), end)
+		return nil, errors.AtOffset(err, start)
+	}
+	return node, nil
+}
+
+// FormatRLP returns the dump of the rlp bytes written by PrintRLP
+func FormatRLP(b []byte) (string, error) {
+	var sb strings.Builder
+	err := PrintRLP(&sb, b)
+	return sb.String(), err
+}
+
+// PrintRLP writes an indented, annotated dump of every value of the rlp bytes. When the bytes cannot be decoded the
+// values decoded before the error are written and the error is returned.
+func PrintRLP(w io.Writer, b []byte) error {
+	nodes, decodeErr := DecodeRLPTree(reader.NewReader(b))
+	for _, node := range nodes {
+		if err := node.Print(w); err != nil {
+			return err
+		}
+	}
+	return decodeErr
+}
+
+// Print writes an indented, annotated dump of the node and its items, one value per line:
+//
+//	list @0 header=2 payload=98 items=9
+//	  string @2 header=0 payload=1: 0x05 = 5
+//
+// Strings that are typed tx envelopes (a type byte followed by a list) are expanded too.
+func (n *RLPNode) Print(w io.Writer) error {
+	return n.print(w, 0)
+}
+
+func (n *RLPNode) print(w io.Writer, depth int) error {
+	indent := strings.Repeat("  ", depth)
+	if n.Kind == RlpList {
+		_, err := fmt.Fprintf(w, "%slist @%d header=%d payload=%d items=%d\n", indent, n.Offset, n.HeaderLength, len(n.Payload), len(n.Children))
+		if err != nil {
+			return err
+		}
+		for _, child := range n.Children {
+			if err = child.print(w, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	_, err := fmt.Fprintf(w, "%sstring @%d header=%d payload=%d: %s\n", indent, n.Offset, n.HeaderLength, len(n.Payload), annotateRLPString(n.Payload))
+	if err != nil {
+		return err
+	}
+	if envelope := typedEnvelope(n); envelope != nil {
+		_, err = fmt.Fprintf(w, "%s  typed tx type=%d\n", indent, n.Payload[0])
+		if err != nil {
+			return err
+		}
+		return envelope.print(w, depth+2)
+	}
+	return nil
+}
+
+// typedEnvelope returns the list of a string holding a typed tx envelope (type byte + list), nil if it is not one
+func typedEnvelope(n *RLPNode) *RLPNode {
+	if len(n.Payload) < 2 || n.Payload[0] >= 0x7f {
+		return nil
+	}
+	r := reader.NewReader(n.Payload[1:])
+	if !r.IsNextValAList() {
+		return nil
+	}
+	envelope, err := DecodeRLPNode(r)
+	if err != nil || r.Len() != 0 {
+		return nil
+	}
+	// make the offsets absolute
+	shiftRLPNode(envelope, n.Offset+n.HeaderLength+1)
+	return envelope
+}
+
+func shiftRLPNode(n *RLPNode, delta uint64) {
+	n.Offset += delta
+	for _, child := range n.Children {
+		shiftRLPNode(child, delta)
+	}
+}
+
+// annotateRLPString renders a string payload as hex, adding its value as an integer when it is a canonical one of
+// up to 8 bytes and as text when it is printable ASCII. Payloads longer than 64 bytes are truncated.
+func annotateRLPString(payload []byte) string {
+	if len(payload) == 0 {
+		return "empty"
+	}
+	var annotation string
+	if len(payload) <= 8 && payload[0] != 0 {
+		annotation = fmt.Sprintf(" = %d", reader.BytesToUint64(payload))
+	}
+	if len(payload) >= 3 && isPrintable(payload) {
+		annotation += fmt.Sprintf(" %q", payload)
+	}
+	if len(payload) > 64 {
+		return "0x" + hex.EncodeToString(payload[:32]) + "..." + annotation
+	}
+	return "0x" + hex.EncodeToString(payload) + annotation
+}
+
+func isPrintable(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package genTx
+
+import (
+	"bytes"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// compareRLPNodeForTests checks the node against go-ethereum's view of the same encoding
+func compareRLPNodeForTests(t *testing.T, node *RLPNode, enc []byte) {
+	kind, content, rest, err := rlp.Split(enc)
+	assert.NoError(t, err)
+	assert.Empty(t, rest)
+	assert.Equal(t, uint64(len(enc)), node.Len())
+	assert.Equal(t, content, node.Payload)
+	if kind != rlp.List {
+		assert.Equal(t, RlpString, node.Kind)
+		assert.Nil(t, node.Children)
+		return
+	}
+	assert.Equal(t, RlpList, node.Kind)
+	for i := 0; len(content) > 0; i++ {
+		_, _, rest, err = rlp.Split(content)
+		assert.NoError(t, err)
+		if !assert.Less(t, i, len(node.Children)) {
+			return
+		}
+		child := node.Children[i]
+		assert.Equal(t, node.Offset+node.HeaderLength+uint64(len(node.Payload)-len(content)), child.Offset)
+		compareRLPNodeForTests(t, child, content[:len(content)-len(rest)])
+		content = rest
+	}
+}
+
+func TestDecodeRLPTree(t *testing.T) {
+	txs := fuzzSeedTxsForTests(t)
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(t, err)
+
+	nodes, err := DecodeRLPTree(reader.NewReader(packet))
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 1)
+	assert.Len(t, nodes[0].Children, len(txs))
+	compareRLPNodeForTests(t, nodes[0], packet)
+
+	// every typed tx of the packet is expanded by the printer
+	dump, err := FormatRLP(packet)
+	assert.NoError(t, err)
+	assert.Equal(t, len(txs)-1, bytes.Count([]byte(dump), []byte("typed tx type=")))
+
+	// the values decoded before the error are kept
+	nodes, err = DecodeRLPTree(reader.NewReader(append([]byte{0x01, 0x02}, packet[:len(packet)-1]...)))
+	assert.Error(t, err)
+	assert.Len(t, nodes, 2)
+
+	// items overrunning their list
+	_, err = DecodeRLPTree(reader.NewReader([]byte{0xc2, 0x82, 0x01, 0x02}))
+	assert.Error(t, err)
+
+	// nested too deep
+	deep := bytes.Repeat([]byte{0xc1}, MaxRLPTreeDepth+1)
+	deep = append(deep, 0xc0)
+	_, err = DecodeRLPTree(reader.NewReader(deep))
+	assert.Error(t, err)
+}
+
+func TestFormatRLP(t *testing.T) {
+	// [5, "dog", [], 0x0102, ""] followed by the typed envelope 0x02 [1]
+	b := []byte{0xca, 0x05, 0x83, 'd', 'o', 'g', 0xc0, 0x82, 0x01, 0x02, 0x80, 0x83, 0x02, 0xc1, 0x01}
+	dump, err := FormatRLP(b)
+	assert.NoError(t, err)
+	assert.Equal(t, `list @0 header=1 payload=10 items=5
+  string @1 header=0 payload=1: 0x05 = 5
+  string @2 header=1 payload=3: 0x646f67 = 6582119 "dog"
+  list @6 header=1 payload=0 items=0
+  string @7 header=1 payload=2: 0x0102 = 258
+  string @10 header=1 payload=0: empty
+string @11 header=1 payload=3: 0x02c101 = 180481
+  typed tx type=2
+    list @13 header=1 payload=1 items=1
+      string @14 header=0 payload=1: 0x01 = 1
+`, dump)
+}
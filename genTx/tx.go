@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"golang.org/x/crypto/sha3"
 	"math/big"
+	"slices"
 )
 
 var zeroHash = common.Hash{}
@@ -22,10 +23,17 @@ type CustomTx struct {
 	startTx            int // Points where the tx info starts. Used for doing the signed hash
 	startTxDataPointer int // Point where the tx data starts.
 	startTxSignature   int // Point where the tx signature starts.
+	// Only used by blob txs decoded in their network form (tx payload + sidecar). The signed hash is calculated over
+	// the txType and the inner tx payload list, which is not contiguous with the txType in the rlp bytes.
+	startBlobTxPayload int
+	endBlobTxPayload   int
 
 	SignedRlpBytes   []byte
 	UnsignedRlpBytes []byte // only used when the tx is unsigned. Since it can be helpful to fill the signed tx. Stores only txData not preTxType nor the total length
 
+	// signer used for legacy txs, when nil it is derived from the tx. See Signer
+	signer *Signer
+
 	// setup bytes since we need to check for length
 	from []byte
 	// needed for any type
@@ -55,8 +63,65 @@ type CustomTx struct {
 	rlpSignedBytesLength int // used to cache the length of the rlpbytes of the entire tx
 	rlpSignedBytesTxInfo int // used to cache the length of the rlpbytes of the tx values (without adding the rlp listsize or txtype + valueSize + listsize)
 
+	// allocations kept by Reset to be reused when decoding into the tx. See DecodeTxInto
+	spare spareValues
+}
+
+// spareValues holds the values of a reset tx that the next decode into it reuses
+type spareValues struct {
+	ints       []*big.Int
+	to         *common.Address
+	accessList types.AccessList
+	blobHashes []common.Hash
+	authList   []types.SetCodeAuthorization
+}
+
+// Reset clears the tx so it can be decoded into again, see DecodeTxInto. Its big.Ints, address and lists are kept to
+// be reused by the next decode, so values taken from the tx must not be used after resetting it, and the tx must own
+// them: they cannot be shared with other txs or between its own fields.
+func (tx *CustomTx) Reset() {
+	spare := tx.spare
+	for _, v := range [...]*big.Int{tx.GasPrice, tx.Value, tx.V, tx.R, tx.S, tx.ChainID, tx.GasTipCap, tx.GasFeeCap, tx.BlobFeeCap} {
+		if v != nil {
+			spare.ints = append(spare.ints, v)
+		}
+	}
+	if tx.To != nil {
+		spare.to = tx.To
+	}
+	if tx.AccessList != nil {
+		spare.accessList = tx.AccessList
+	}
+	if tx.BlobHashes != nil {
+		spare.blobHashes = tx.BlobHashes
+	}
+	if tx.AuthList != nil {
+		spare.authList = tx.AuthList
+	}
+	*tx = CustomTx{spare: spare}
+}
+
+// newBigInt returns a big.Int set to the big endian bytes b, reusing one kept by Reset when there is one
+func (tx *CustomTx) newBigInt(b []byte) *big.Int {
+	if n := len(tx.spare.ints); n > 0 {
+		v := tx.spare.ints[n-1]
+		tx.spare.ints = tx.spare.ints[:n-1]
+		return v.SetBytes(b)
+	}
+	return new(big.Int).SetBytes(b)
 }
 
+// newAddress returns an address, reusing the one kept by Reset when there is one
+func (tx *CustomTx) newAddress() *common.Address {
+	if to := tx.spare.to; to != nil {
+		tx.spare.to = nil
+		return to
+	}
+	return new(common.Address)
+}
+
+// FromTx sets the fields of the tx to the ones of normalTx. The integers and lists go-ethereum returns without copying
+// them are copied, so resetting the tx and decoding into it never modifies normalTx.
 func (tx *CustomTx) FromTx(normalTx *types.Transaction) error {
 	// copy standard parameters
 	tx.Nonce = normalTx.Nonce()
@@ -67,39 +132,48 @@ func (tx *CustomTx) FromTx(normalTx *types.Transaction) error {
 	tx.TxType = normalTx.Type()
 
 	v, r, s := normalTx.RawSignatureValues()
-	tx.V = v
-	tx.R = r
-	tx.S = s
+	tx.V = copyBigInt(v)
+	tx.R = copyBigInt(r)
+	tx.S = copyBigInt(s)
 
 	switch normalTx.Type() {
 	case types.LegacyTxType:
 		tx.GasPrice = normalTx.GasPrice()
 	case types.AccessListTxType:
 		tx.GasPrice = normalTx.GasPrice()
-		tx.AccessList = normalTx.AccessList()
-		tx.ChainID = normalTx.ChainId()
+		tx.AccessList = cloneAccessList(normalTx.AccessList())
+		tx.ChainID = copyBigInt(normalTx.ChainId())
 	case types.DynamicFeeTxType:
 		tx.GasFeeCap = normalTx.GasFeeCap()
 		tx.GasTipCap = normalTx.GasTipCap()
-		tx.AccessList = normalTx.AccessList()
-		tx.ChainID = normalTx.ChainId()
+		tx.AccessList = cloneAccessList(normalTx.AccessList())
+		tx.ChainID = copyBigInt(normalTx.ChainId())
 	case types.BlobTxType:
 		tx.GasFeeCap = normalTx.GasFeeCap()
 		tx.GasTipCap = normalTx.GasTipCap()
-		tx.AccessList = normalTx.AccessList()
-		tx.ChainID = normalTx.ChainId()
-		tx.BlobFeeCap = normalTx.BlobGasFeeCap()
-		tx.BlobHashes = normalTx.BlobHashes()
+		tx.AccessList = cloneAccessList(normalTx.AccessList())
+		tx.ChainID = copyBigInt(normalTx.ChainId())
+		tx.BlobFeeCap = copyBigInt(normalTx.BlobGasFeeCap())
+		tx.BlobHashes = slices.Clone(normalTx.BlobHashes())
+		tx.Sidecar = normalTx.BlobTxSidecar()
 	case types.SetCodeTxType:
 		tx.GasFeeCap = normalTx.GasFeeCap()
 		tx.GasTipCap = normalTx.GasTipCap()
-		tx.AccessList = normalTx.AccessList()
-		tx.ChainID = normalTx.ChainId()
-		tx.AuthList = normalTx.SetCodeAuthorizations()
+		tx.AccessList = cloneAccessList(normalTx.AccessList())
+		tx.ChainID = copyBigInt(normalTx.ChainId())
+		tx.AuthList = slices.Clone(normalTx.SetCodeAuthorizations())
 	}
 	return nil
 }
 
+// copyBigInt returns a copy of v, nil if v is nil
+func copyBigInt(v *big.Int) *big.Int {
+	if v == nil {
+		return nil
+	}
+	return new(big.Int).Set(v)
+}
+
 func (tx *CustomTx) CalculateRLPLengthSignatureValues() int {
 	return CalculateRLBigIntValueLength(tx.V) + CalculateRLBigIntValueLength(tx.R) + CalculateRLBigIntValueLength(tx.S)
 }
@@ -107,7 +181,7 @@ func (tx *CustomTx) CalculateRLPLengthSignatureValues() int {
 // Measure the length of a tx including the
 func (tx *CustomTx) CalculateRLPSignedBytesLength() (int, int, error) {
 	if tx.rlpSignedBytesLength != 0 && tx.rlpSignedBytesTxInfo != 0 {
-		return len(tx.SignedRlpBytes), tx.rlpSignedBytesTxInfo, nil
+		return tx.rlpSignedBytesLength, tx.rlpSignedBytesTxInfo, nil
 	}
 	var (
 		l, valsLength int
@@ -119,27 +193,47 @@ func (tx *CustomTx) CalculateRLPSignedBytesLength() (int, int, error) {
 	case types.DynamicFeeTxType:
 		valsLength = tx.calculateRLPSignedBytesLenDynamicFeesTx()
 		l = CalculateNBytesLength(uint64(CalculateRLPListLength(valsLength) + 1))
+	case types.SetCodeTxType:
+		valsLength = tx.calculateRLPSignedBytesLenSetCodeTx()
+		l = CalculateNBytesLength(uint64(CalculateRLPListLength(valsLength) + 1))
+	case types.BlobTxType:
+		valsLength = tx.calculateRLPSignedBytesLenBlobTx()
+		if tx.Sidecar != nil {
+			// network form, the tx payload list is wrapped in another list together with the sidecar
+			l = CalculateNBytesLength(uint64(CalculateRLPListLength(CalculateRLPListLength(valsLength)+tx.calculateRLPBlobTxSidecarLength()) + 1))
+		} else {
+			l = CalculateNBytesLength(uint64(CalculateRLPListLength(valsLength) + 1))
+		}
 	case types.LegacyTxType:
 		valsLength = tx.calculateRLPSignedBytesLenLegacyTx()
 		l = CalculateRLPListLength(valsLength)
 	default:
 		return 0, 0, errors.ErrTxTypeNotSupported
 	}
-	tx.rlpSignedBytesTxInfo = l
-	tx.rlpSignedBytesLength = valsLength
+	tx.rlpSignedBytesLength = l
+	tx.rlpSignedBytesTxInfo = valsLength
 	return l, valsLength, nil
 
 }
 
 func (tx *CustomTx) calculateRLPAccessListLength() int {
+	return accessListRLPLength(tx.AccessList)
+}
+
+func (tx *CustomTx) calculateRLPAccessTupleLength(accessTuple types.AccessTuple) int {
+	return accessTupleRLPLength(accessTuple)
+}
+
+// accessListRLPLength returns the length of the items of the access list encoded as rlp
+func accessListRLPLength(accessList types.AccessList) int {
 	var length int
-	for _, a := range tx.AccessList {
-		length += CalculateRLPListLength(tx.calculateRLPAccessTupleLength(a))
+	for _, a := range accessList {
+		length += CalculateRLPListLength(accessTupleRLPLength(a))
 	}
 	return length
 }
 
-func (tx *CustomTx) calculateRLPAccessTupleLength(accessTuple types.AccessTuple) int {
+func accessTupleRLPLength(accessTuple types.AccessTuple) int {
 	// this can be precalculated much easier
 	// storage keys
 	length := CalculateRLPListLength(len(accessTuple.StorageKeys) * HashRLPLength)
@@ -216,6 +310,119 @@ func (tx *CustomTx) calculateRLPUnSignedBytesLenDynamicFeesTx() int {
 	return length
 }
 
+func (tx *CustomTx) calculateRLPAuthListLength() int {
+	return authListRLPLength(tx.AuthList)
+}
+
+func (tx *CustomTx) calculateRLPAuthorizationLength(auth *types.SetCodeAuthorization) int {
+	return authorizationRLPLength(auth)
+}
+
+// authListRLPLength returns the length of the items of the authorization list encoded as rlp
+func authListRLPLength(authList []types.SetCodeAuthorization) int {
+	var length int
+	for i := range authList {
+		length += CalculateRLPListLength(authorizationRLPLength(&authList[i]))
+	}
+	return length
+}
+
+func authorizationRLPLength(auth *types.SetCodeAuthorization) int {
+	var length int
+	length += CalculateRLUint256ValueLength(&auth.ChainID)
+	length += AddressRLPLength
+	length += CalculateRLP64ValueLength(auth.Nonce)
+	length += CalculateRLP64ValueLength(uint64(auth.V))
+	length += CalculateRLUint256ValueLength(&auth.R)
+	length += CalculateRLUint256ValueLength(&auth.S)
+	return length
+}
+
+func (tx *CustomTx) calculateRLPSignedBytesLenSetCodeTx() int {
+	var length int
+	if len(tx.UnsignedRlpBytes) > 0 {
+		length += len(tx.UnsignedRlpBytes)
+	} else {
+		length += tx.calculateRLPUnSignedBytesLenSetCodeTx()
+	}
+	length += CalculateRLBigIntValueLength(tx.V)
+	length += CalculateRLBigIntValueLength(tx.R)
+	length += CalculateRLBigIntValueLength(tx.S)
+	return length
+}
+
+func (tx *CustomTx) calculateRLPUnSignedBytesLenSetCodeTx() int {
+	length := tx.calculateRLPUnSignedBytesLenDynamicFeesTx()
+	length += CalculateRLPListLength(tx.calculateRLPAuthListLength())
+	return length
+}
+
+func (tx *CustomTx) calculateRLPBlobHashesLength() int {
+	return len(tx.BlobHashes) * HashRLPLength
+}
+
+func (tx *CustomTx) calculateRLPSignedBytesLenBlobTx() int {
+	var length int
+	if len(tx.UnsignedRlpBytes) > 0 {
+		length += len(tx.UnsignedRlpBytes)
+	} else {
+		length += tx.calculateRLPUnSignedBytesLenBlobTx()
+	}
+	length += CalculateRLBigIntValueLength(tx.V)
+	length += CalculateRLBigIntValueLength(tx.R)
+	length += CalculateRLBigIntValueLength(tx.S)
+	return length
+}
+
+// calculateRLPBlobTxSidecarLength returns the length of the blobs, commitments and proofs lists, including their
+// list prefixes
+func (tx *CustomTx) calculateRLPBlobTxSidecarLength() int {
+	blobsLength, commitmentsLength, proofsLength := blobTxSidecarRLPLengths(tx.Sidecar)
+	return CalculateRLPListLength(blobsLength) + CalculateRLPListLength(commitmentsLength) + CalculateRLPListLength(proofsLength)
+}
+
+// blobTxSidecarRLPLengths returns the length of the items of the blobs, commitments and proofs lists encoded as rlp
+func blobTxSidecarRLPLengths(sidecar *types.BlobTxSidecar) (blobsLength, commitmentsLength, proofsLength int) {
+	for i := range sidecar.Blobs {
+		blobsLength += CalculateRLPBytesLength(sidecar.Blobs[i][:])
+	}
+	for i := range sidecar.Commitments {
+		commitmentsLength += CalculateRLPBytesLength(sidecar.Commitments[i][:])
+	}
+	for i := range sidecar.Proofs {
+		proofsLength += CalculateRLPBytesLength(sidecar.Proofs[i][:])
+	}
+	return blobsLength, commitmentsLength, proofsLength
+}
+
+func (tx *CustomTx) calculateRLPUnSignedBytesLenBlobTx() int {
+	var length int
+	length += CalculateRLBigIntValueLength(tx.ChainID)
+	length += CalculateRLP64ValueLength(tx.Nonce)
+	length += CalculateRLBigIntValueLength(tx.GasTipCap)
+	length += CalculateRLBigIntValueLength(tx.GasFeeCap)
+	length += CalculateRLP64ValueLength(tx.Gas)
+	if tx.To != nil {
+		length += AddressRLPLength
+	} else {
+		length += 1
+	}
+	if tx.Value != nil {
+		length += CalculateRLBigIntValueLength(tx.Value)
+	} else {
+		length += 1
+	}
+	length += CalculateRLPBytesLength(tx.Data)
+	length += CalculateRLPListLength(tx.calculateRLPAccessListLength())
+	if tx.BlobFeeCap != nil {
+		length += CalculateRLBigIntValueLength(tx.BlobFeeCap)
+	} else {
+		length += 1
+	}
+	length += CalculateRLPListLength(tx.calculateRLPBlobHashesLength())
+	return length
+}
+
 func (tx *CustomTx) calculateRLPSignedBytesLenLegacyTx() int {
 	var length int
 	if len(tx.UnsignedRlpBytes) > 0 {
@@ -261,6 +468,10 @@ func (tx *CustomTx) CalculateSignedHash() common.Hash {
 	hasher := sha3.NewLegacyKeccak256().(crypto.KeccakState)
 	if tx.TxType == types.LegacyTxType {
 		hasher.Write(tx.SignedRlpBytes)
+	} else if tx.endBlobTxPayload > 0 {
+		// network form of a blob tx, the hash only covers the txType and the tx payload without the sidecar
+		hasher.Write([]byte{tx.TxType})
+		hasher.Write(tx.SignedRlpBytes[tx.startBlobTxPayload:tx.endBlobTxPayload])
 	} else {
 		hasher.Write(tx.SignedRlpBytes[tx.startTx:])
 	}
@@ -287,25 +498,50 @@ func (tx *CustomTx) Hash() common.Hash {
 func (tx *CustomTx) From() (common.Address, error) {
 	if len(tx.from) != 0 {
 		return common.BytesToAddress(tx.from), nil
-	} else {
-		var err error
-		switch tx.TxType {
-		case types.LegacyTxType:
-			tx.from, err = tx.getFromLegacyTx()
-			return common.BytesToAddress(tx.from), err
-		case types.DynamicFeeTxType, types.AccessListTxType:
-			tx.from, err = tx.getFromOtherTxTypes()
-			return common.BytesToAddress(tx.from), err
-		default:
-			return common.Address{}, errors.ErrTxTypeNotSupported
+	}
+	// when the sender cache is enabled look for the sender before doing the ecrecover
+	cache := senderCache.Load()
+	var hash common.Hash
+	if cache != nil {
+		if hash = tx.Hash(); hash == zeroHash {
+			// the tx cannot be encoded, so it cannot be cached either
+			cache = nil
+		} else if from, ok := cache.Get(hash); ok {
+			tx.from = from.Bytes()
+			return from, nil
 		}
 	}
-
+	var err error
+	switch tx.TxType {
+	case types.LegacyTxType:
+		tx.from, err = tx.getFromLegacyTx()
+	case types.DynamicFeeTxType, types.AccessListTxType, types.BlobTxType, types.SetCodeTxType:
+		tx.from, err = tx.getFromOtherTxTypes()
+	default:
+		return common.Address{}, errors.ErrTxTypeNotSupported
+	}
+	from := common.BytesToAddress(tx.from)
+	if cache != nil && err == nil {
+		cache.Add(hash, from)
+	}
+	return from, err
 }
 
 func (tx *CustomTx) getFromLegacyTx() ([]byte, error) {
-	V := byte(tx.V.Uint64() - V_MULTIPLER_UINT64 - 8 - 27)
-	//V := c.v[0]
+	v := new(big.Int)
+	if signer := tx.Signer(); signer.Protected() {
+		// EIP-155: V = recoveryId + 35 + chainId * 2
+		v.Sub(tx.V, big35)
+		v.Sub(v, signer.vMultiplier)
+	} else {
+		// pre EIP-155: V = recoveryId + 27
+		v.Sub(tx.V, big27)
+	}
+	// the recovery id must be 0 or 1, any other V belongs to another chain or is invalid
+	if v.Sign() < 0 || v.Cmp(common.Big1) > 0 {
+		return []byte{}, errors.ErrInvalidSig
+	}
+	V := byte(v.Uint64())
 	if !validateSignatureValues(V, tx.R, tx.S, false) {
 		return []byte{}, errors.ErrInvalidSig
 	}
@@ -325,7 +561,7 @@ func (tx *CustomTx) getFromLegacyTx() ([]byte, error) {
 	if len(pub) == 0 || pub[0] != 4 {
 		return []byte{}, errors.ErrInvalidPkb
 	}
-	return crypto.Keccak256(pub[1:])[12:], nil
+	return pubkeyToAddressBytes(pub), nil
 }
 
 func (tx *CustomTx) getFromOtherTxTypes() ([]byte, error) {
@@ -351,7 +587,17 @@ func (tx *CustomTx) getFromOtherTxTypes() ([]byte, error) {
 	//copy(addr[:], crypto.Keccak256(pub[1:])[12:])
 	//hash, _ := c.GetSignedHashBytes()
 	//fmt.Printf("Hash: %x, \t From 0x%x: \n", hash, crypto.Keccak256(pub[1:])[12:])
-	return crypto.Keccak256(pub[1:])[12:], nil
+	return pubkeyToAddressBytes(pub), nil
+}
+
+// pubkeyToAddressBytes returns the address bytes of an uncompressed public key using a pooled hasher
+func pubkeyToAddressBytes(pub []byte) []byte {
+	hasher := pool.GetHasher()
+	defer pool.PutHasher(hasher)
+	hasher.Write(pub[1:])
+	h := make([]byte, 32)
+	hasher.Read(h)
+	return h[12:]
 }
 
 func (tx *CustomTx) CalculateUnsignedHash() common.Hash {
@@ -367,6 +613,10 @@ func (tx *CustomTx) CalculateUnsignedHash() common.Hash {
 		tx.EncodeUnsignedDynamicFeesTx(buffer)
 	case types.AccessListTxType:
 		tx.EncodeUnsignedAccessListTx(buffer)
+	case types.BlobTxType:
+		tx.EncodeUnsignedBlobTx(buffer)
+	case types.SetCodeTxType:
+		tx.EncodeUnsignedSetCodeTx(buffer)
 	default:
 		return zeroHash
 	}
@@ -380,6 +630,9 @@ func (tx *CustomTx) SignTx(key *ecdsa.PrivateKey) error {
 	hasher := pool.GetHasher()
 	defer pool.PutHasher(hasher)
 
+	if tx.TxType != types.LegacyTxType && tx.ChainID == nil && tx.signer != nil && tx.signer.Protected() {
+		tx.ChainID = tx.signer.ChainID()
+	}
 	h := tx.UnsignedHash()
 	sig, err := crypto.Sign(h.Bytes(), key)
 	if err != nil {
@@ -391,7 +644,11 @@ func (tx *CustomTx) SignTx(key *ecdsa.PrivateKey) error {
 	tx.V = new(big.Int)
 	switch tx.TxType {
 	case types.LegacyTxType:
-		tx.V.Add(big.NewInt(int64(sig[64]+35)), V_MULTIPLIER)
+		if signer := tx.Signer(); signer.Protected() {
+			tx.V.Add(big.NewInt(int64(sig[64]+35)), signer.vMultiplier)
+		} else {
+			tx.V.SetUint64(uint64(sig[64] + 27))
+		}
 	default:
 		if sig[64] > 0 {
 			tx.V.SetUint64(uint64(sig[64]))
@@ -404,10 +661,13 @@ func (tx *CustomTx) SignTx(key *ecdsa.PrivateKey) error {
 // - V, R, S values
 // - SignedRlpBytes
 // - signedHash
+// - the cached lengths of the signed rlp
 func (tx *CustomTx) ResetSignedVals() {
 	tx.V = nil
 	tx.R = nil
 	tx.S = nil
 	tx.signedHash = []byte{}
 	tx.SignedRlpBytes = []byte{}
+	tx.rlpSignedBytesLength = 0
+	tx.rlpSignedBytesTxInfo = 0
 }
@@ -0,0 +1,769 @@
+package genTx
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/pool"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"math/big"
+)
+
+// Tx256 is the same as CustomTx but storing its integers as uint256.Int values instead of *big.Int, so decoding,
+// encoding and recovering the sender of a tx do not allocate for them. Integers the tx type does not have are zero.
+//
+// Like CustomTx, a decoded Tx256 points to the decoded bytes, which must not be modified while the tx is used. The
+// hashes and sender are cached, so after changing the fields of a tx its SignedRlpBytes must be cleared with
+// ResetSignedVals.
+type Tx256 struct {
+	TxType uint8
+	Nonce  uint64
+	Gas    uint64
+	To     *common.Address
+	Data   []byte
+
+	ChainID    uint256.Int
+	GasPrice   uint256.Int // only available for legacy and access list txs
+	GasTipCap  uint256.Int // a.k.a. maxPriorityFeePerGas
+	GasFeeCap  uint256.Int // a.k.a. maxFeePerGas
+	Value      uint256.Int
+	BlobFeeCap uint256.Int
+	V, R, S    uint256.Int
+
+	AccessList types.AccessList
+	BlobHashes []common.Hash
+	Sidecar    *types.BlobTxSidecar
+	AuthList   []types.SetCodeAuthorization
+
+	SignedRlpBytes []byte
+
+	// offsets in SignedRlpBytes, see CustomTx
+	startTx            int
+	startTxDataPointer int
+	startTxSignature   int
+	startBlobTxPayload int
+	endBlobTxPayload   int
+
+	signedHash      common.Hash
+	unsignedHash    common.Hash
+	from            common.Address
+	hasSignedHash   bool
+	hasUnsignedHash bool
+	hasFrom         bool
+
+	// signer used for legacy txs, when nil it is derived from the tx. See Signer
+	signer *Signer
+}
+
+// DecodeTxsPacket256 decodes a list of transactions as Tx256. Txs of unsupported types are skipped.
+func DecodeTxsPacket256(r *reader.RlpReader) ([]*Tx256, error) {
+	var txs []*Tx256
+	listSize, err := r.ReadListSize()
+	if err != nil {
+		return nil, err
+	}
+	cPos := r.Pos()
+	for i := 0; r.Pos()-cPos < listSize; i++ {
+		tx, err := DecodeTx256(r)
+		if err != nil {
+			if errors.Is(err, errors.ErrTxTypeNotSupported) {
+				continue
+			}
+			return txs, errors.WithTxIndex(err, i)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// DecodeTxsPacket256Into is the same as DecodeTxsPacket256 but decoding into the txs of dst with DecodeTx256Into, see
+// DecodeTxsPacketInto
+func DecodeTxsPacket256Into(r *reader.RlpReader, dst []*Tx256) ([]*Tx256, error) {
+	txs := dst[:0]
+	listSize, err := r.ReadListSize()
+	if err != nil {
+		return txs, err
+	}
+	cPos := r.Pos()
+	for i := 0; r.Pos()-cPos < listSize; i++ {
+		var tx *Tx256
+		if len(txs) < cap(txs) {
+			tx = txs[:len(txs)+1][len(txs)]
+		}
+		if tx == nil {
+			tx = new(Tx256)
+		}
+		err = DecodeTx256Into(r, tx)
+		if err != nil {
+			if errors.Is(err, errors.ErrTxTypeNotSupported) {
+				continue
+			}
+			return txs, errors.WithTxIndex(err, i)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// DecodeTx256 decodes the next transaction of the provided RlpReader, whatever its type is.
+// Returns errors.ErrTxTypeNotSupported, after skipping it, if the tx type is not supported.
+func DecodeTx256(r *reader.RlpReader) (*Tx256, error) {
+	tx := new(Tx256)
+	if err := DecodeTx256Into(r, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// DecodeTx256Into is the same as DecodeTx256 but decoding into tx, reusing its address and lists. Values taken from
+// tx before must not be used after calling it. On error tx is left partially decoded.
+func DecodeTx256Into(r *reader.RlpReader, tx *Tx256) error {
+	to, accessList, blobHashes, authList := tx.To, tx.AccessList, tx.BlobHashes, tx.AuthList
+	*tx = Tx256{}
+	parts, err := readTxFields(r, func(field txField, _ int) (err error) {
+		switch field {
+		case fieldNonce:
+			tx.Nonce, err = r.DecodeUint64()
+		case fieldGas:
+			tx.Gas, err = r.DecodeUint64()
+		case fieldTo:
+			var toBytes []byte
+			if toBytes, err = decodeTo(r); err == nil && len(toBytes) > 0 {
+				if to == nil {
+					to = new(common.Address)
+				}
+				tx.To = to
+				tx.To.SetBytes(toBytes)
+			}
+		case fieldData:
+			tx.Data, err = r.DecodeNextValue()
+		case fieldAccessList:
+			tx.AccessList, err = decodeAccessListInto(r, accessList)
+		case fieldBlobHashes:
+			tx.BlobHashes, err = decodeBlobHashesInto(r, blobHashes)
+		case fieldAuthList:
+			tx.AuthList, err = decodeSetCodeAuthorizationListInto(r, authList)
+		case fieldSidecar:
+			tx.Sidecar, err = DecodeBlobTxSidecar(r)
+		default:
+			*tx.uint256Field(field), err = decodeUint256(r)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	tx.TxType = parts.txType
+	tx.SignedRlpBytes = parts.rlpBytes
+	tx.startTx = parts.startTx
+	tx.startTxDataPointer = parts.startTxDataPointer
+	tx.startTxSignature = parts.startTxSignature
+	tx.startBlobTxPayload = parts.startBlobTxPayload
+	tx.endBlobTxPayload = parts.endBlobTxPayload
+	return nil
+}
+
+// uint256Field returns where the integer field is stored
+func (tx *Tx256) uint256Field(field txField) *uint256.Int {
+	switch field {
+	case fieldChainID:
+		return &tx.ChainID
+	case fieldGasPrice:
+		return &tx.GasPrice
+	case fieldGasTipCap:
+		return &tx.GasTipCap
+	case fieldGasFeeCap:
+		return &tx.GasFeeCap
+	case fieldValue:
+		return &tx.Value
+	case fieldBlobFeeCap:
+		return &tx.BlobFeeCap
+	case fieldV:
+		return &tx.V
+	case fieldR:
+		return &tx.R
+	default:
+		return &tx.S
+	}
+}
+
+// fieldLength returns the length of the field encoded as rlp
+func (tx *Tx256) fieldLength(field txField) int {
+	switch field {
+	case fieldNonce:
+		return CalculateRLP64ValueLength(tx.Nonce)
+	case fieldGas:
+		return CalculateRLP64ValueLength(tx.Gas)
+	case fieldTo:
+		if tx.To == nil {
+			return 1
+		}
+		return AddressRLPLength
+	case fieldData:
+		return CalculateRLPBytesLength(tx.Data)
+	case fieldAccessList:
+		return CalculateRLPListLength(accessListRLPLength(tx.AccessList))
+	case fieldBlobHashes:
+		return CalculateRLPListLength(len(tx.BlobHashes) * HashRLPLength)
+	case fieldAuthList:
+		return CalculateRLPListLength(authListRLPLength(tx.AuthList))
+	default:
+		return CalculateRLUint256ValueLength(tx.uint256Field(field))
+	}
+}
+
+// writeField writes the field encoded as rlp
+func (tx *Tx256) writeField(buffer *bytes.Buffer, field txField) error {
+	switch field {
+	case fieldNonce:
+		return WriteRLPUint64(buffer, tx.Nonce)
+	case fieldGas:
+		return WriteRLPUint64(buffer, tx.Gas)
+	case fieldTo:
+		if tx.To == nil {
+			return buffer.WriteByte(ZeroUint64RLPVal)
+		}
+		return WriteRLPBytes(buffer, tx.To[:])
+	case fieldData:
+		return WriteRLPBytes(buffer, tx.Data)
+	case fieldAccessList:
+		return writeAccessList(buffer, tx.AccessList)
+	case fieldBlobHashes:
+		return writeBlobHashes(buffer, tx.BlobHashes)
+	case fieldAuthList:
+		return writeAuthList(buffer, tx.AuthList)
+	default:
+		return WriteRLPUint256(buffer, tx.uint256Field(field))
+	}
+}
+
+// fieldsLength returns the length of the fields encoded as rlp
+func (tx *Tx256) fieldsLength(fields []txField) int {
+	var length int
+	for _, field := range fields {
+		length += tx.fieldLength(field)
+	}
+	return length
+}
+
+func (tx *Tx256) writeFields(buffer *bytes.Buffer, fields []txField) error {
+	for _, field := range fields {
+		if err := tx.writeField(buffer, field); err != nil {
+			return errors.WithField(err, txFieldNames[field])
+		}
+	}
+	return nil
+}
+
+// sidecarLength returns the length of the blobs, commitments and proofs lists of the sidecar encoded as rlp
+func (tx *Tx256) sidecarLength() int {
+	if tx.TxType != types.BlobTxType || tx.Sidecar == nil {
+		return 0
+	}
+	blobsLength, commitmentsLength, proofsLength := blobTxSidecarRLPLengths(tx.Sidecar)
+	return CalculateRLPListLength(blobsLength) + CalculateRLPListLength(commitmentsLength) + CalculateRLPListLength(proofsLength)
+}
+
+// SignedRLPLength returns the length of the signed tx encoded as rlp, as it is written by EncodeSignedRLP
+func (tx *Tx256) SignedRLPLength() (int, error) {
+	if len(tx.SignedRlpBytes) > 0 {
+		return len(tx.SignedRlpBytes), nil
+	}
+	layout, ok := txLayouts[tx.TxType]
+	if !ok {
+		return 0, errors.ErrTxTypeNotSupported
+	}
+	length := CalculateRLPListLength(tx.fieldsLength(layout))
+	if tx.TxType == types.LegacyTxType {
+		return length, nil
+	}
+	if sidecarLength := tx.sidecarLength(); sidecarLength > 0 {
+		length = CalculateRLPListLength(length + sidecarLength)
+	}
+	return CalculateNBytesLength(uint64(length + 1)), nil
+}
+
+// EncodeTxsPacket256 encodes the txs as a list of signed txs
+func EncodeTxsPacket256(buffer *bytes.Buffer, txs []*Tx256) error {
+	var listLength int
+	for _, tx := range txs {
+		l, err := tx.SignedRLPLength()
+		if err != nil {
+			return err
+		}
+		listLength += l
+	}
+	_, err := WriteListLength(buffer, listLength)
+	if err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		err = tx.EncodeSignedRLP(buffer, false)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AppendTxsPacket256 appends the txs encoded as a list of signed txs to dst and returns the extended slice, see
+// AppendTxsPacket
+func AppendTxsPacket256(dst []byte, txs []*Tx256) ([]byte, error) {
+	var listLength int
+	for _, tx := range txs {
+		l, err := tx.SignedRLPLength()
+		if err != nil {
+			return dst, err
+		}
+		listLength += l
+	}
+	start := len(dst)
+	dst = AppendListLength(dst, listLength)
+	var err error
+	for _, tx := range txs {
+		if dst, err = tx.AppendSignedRLP(dst); err != nil {
+			return dst[:start], err
+		}
+	}
+	return dst, nil
+}
+
+// AppendSignedRLP appends the signed tx to dst and returns the extended slice, see CustomTx.AppendSignedRLP
+func (tx *Tx256) AppendSignedRLP(dst []byte) ([]byte, error) {
+	if len(tx.SignedRlpBytes) > 0 {
+		return append(dst, tx.SignedRlpBytes...), nil
+	}
+	buffer := bytes.NewBuffer(dst)
+	if err := tx.EncodeSignedRLP(buffer, false); err != nil {
+		return dst, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// EncodeSignedRLP writes the signed tx, blob txs with a sidecar in their network form. When save is set the written
+// bytes are kept as the SignedRlpBytes of the tx. Txs that already have their SignedRlpBytes write them as they are.
+func (tx *Tx256) EncodeSignedRLP(buffer *bytes.Buffer, save bool) error {
+	if len(tx.SignedRlpBytes) > 0 {
+		_, err := buffer.Write(tx.SignedRlpBytes)
+		return err
+	}
+	layout, ok := txLayouts[tx.TxType]
+	if !ok {
+		return errors.ErrTxTypeNotSupported
+	}
+	start := buffer.Len()
+	fieldsLength := tx.fieldsLength(layout)
+	var parts txParts
+	if tx.TxType != types.LegacyTxType {
+		payloadLength := CalculateRLPListLength(fieldsLength)
+		sidecarLength := tx.sidecarLength()
+		innerLength := payloadLength
+		if sidecarLength > 0 {
+			innerLength = CalculateRLPListLength(payloadLength + sidecarLength)
+		}
+		_, err := WriteValLength(buffer, innerLength+1)
+		if err != nil {
+			return err
+		}
+		parts.startTx = buffer.Len() - start
+		buffer.WriteByte(tx.TxType)
+		if sidecarLength > 0 {
+			_, err = WriteListLength(buffer, payloadLength+sidecarLength)
+			if err != nil {
+				return err
+			}
+			parts.startBlobTxPayload = buffer.Len() - start
+			parts.endBlobTxPayload = parts.startBlobTxPayload + payloadLength
+		}
+	}
+	_, err := WriteListLength(buffer, fieldsLength)
+	if err != nil {
+		return err
+	}
+	parts.startTxDataPointer = buffer.Len() - start
+	if err = tx.writeFields(buffer, layout[:len(layout)-3]); err != nil {
+		return err
+	}
+	parts.startTxSignature = buffer.Len() - start
+	if err = tx.writeFields(buffer, layout[len(layout)-3:]); err != nil {
+		return err
+	}
+	if parts.endBlobTxPayload > 0 {
+		if err = writeBlobTxSidecar(buffer, tx.Sidecar); err != nil {
+			return errors.WithField(err, txFieldNames[fieldSidecar])
+		}
+	}
+	if save {
+		tx.SignedRlpBytes = make([]byte, buffer.Len()-start)
+		copy(tx.SignedRlpBytes, buffer.Bytes()[start:])
+		tx.startTx = parts.startTx
+		tx.startTxDataPointer = parts.startTxDataPointer
+		tx.startTxSignature = parts.startTxSignature
+		tx.startBlobTxPayload = parts.startBlobTxPayload
+		tx.endBlobTxPayload = parts.endBlobTxPayload
+	}
+	return nil
+}
+
+// EncodeUnsignedRLP writes the tx as it is signed: the tx type followed by the tx list without the signature values.
+// Legacy txs have no tx type and end with the values of their signer, see Signer.
+// When the tx has its SignedRlpBytes the fields are taken from them instead of encoding them again.
+func (tx *Tx256) EncodeUnsignedRLP(buffer *bytes.Buffer) error {
+	layout, ok := txLayouts[tx.TxType]
+	if !ok {
+		return errors.ErrTxTypeNotSupported
+	}
+	var signerValues []byte
+	if tx.TxType == types.LegacyTxType {
+		signerValues = tx.Signer().signerValues
+	} else {
+		buffer.WriteByte(tx.TxType)
+	}
+	if len(tx.SignedRlpBytes) > 0 {
+		fields := tx.SignedRlpBytes[tx.startTxDataPointer:tx.startTxSignature]
+		_, err := WriteListLength(buffer, len(fields)+len(signerValues))
+		if err != nil {
+			return err
+		}
+		buffer.Write(fields)
+	} else {
+		fields := layout[:len(layout)-3]
+		_, err := WriteListLength(buffer, tx.fieldsLength(fields)+len(signerValues))
+		if err != nil {
+			return err
+		}
+		if err = tx.writeFields(buffer, fields); err != nil {
+			return err
+		}
+	}
+	_, err := buffer.Write(signerValues)
+	return err
+}
+
+// Hash returns the hash of the signed tx, encoding and keeping its SignedRlpBytes if it has none.
+// Returns the zero hash if the tx cannot be encoded.
+func (tx *Tx256) Hash() common.Hash {
+	if tx.hasSignedHash {
+		return tx.signedHash
+	}
+	if len(tx.SignedRlpBytes) == 0 {
+		buffer := pool.GetRLPBuffer()
+		err := tx.EncodeSignedRLP(buffer, true)
+		pool.PutRLPBuffer(buffer)
+		if err != nil {
+			return zeroHash
+		}
+	}
+	hasher := pool.GetHasher()
+	defer pool.PutHasher(hasher)
+	if tx.TxType == types.LegacyTxType {
+		hasher.Write(tx.SignedRlpBytes)
+	} else if tx.endBlobTxPayload > 0 {
+		// network form of a blob tx, the hash only covers the txType and the tx payload without the sidecar
+		hasher.Write([]byte{tx.TxType})
+		hasher.Write(tx.SignedRlpBytes[tx.startBlobTxPayload:tx.endBlobTxPayload])
+	} else {
+		hasher.Write(tx.SignedRlpBytes[tx.startTx:])
+	}
+	hasher.Read(tx.signedHash[:])
+	tx.hasSignedHash = true
+	return tx.signedHash
+}
+
+// UnsignedHash returns the hash signed by the sender of the tx, see EncodeUnsignedRLP.
+// Returns the zero hash if the tx cannot be encoded.
+func (tx *Tx256) UnsignedHash() common.Hash {
+	if tx.hasUnsignedHash {
+		return tx.unsignedHash
+	}
+	hasher := pool.GetHasher()
+	buffer := pool.GetRLPBuffer()
+	defer pool.PutHasher(hasher)
+	defer pool.PutRLPBuffer(buffer)
+	if err := tx.EncodeUnsignedRLP(buffer); err != nil {
+		return zeroHash
+	}
+	buffer.WriteTo(hasher)
+	hasher.Read(tx.unsignedHash[:])
+	tx.hasUnsignedHash = true
+	return tx.unsignedHash
+}
+
+// SetSigner sets the signer used when signing the tx, recovering its sender and doing the unsigned legacy encoding,
+// see CustomTx.SetSigner
+func (tx *Tx256) SetSigner(s *Signer) {
+	tx.signer = s
+}
+
+// Signer returns the signer of the tx, derived from the tx when none has been set, see CustomTx.Signer
+func (tx *Tx256) Signer() *Signer {
+	if tx.signer != nil {
+		return tx.signer
+	}
+	if tx.TxType == types.LegacyTxType && !tx.V.IsZero() {
+		if tx.V.IsUint64() && (tx.V.Uint64() == 27 || tx.V.Uint64() == 28) {
+			return unprotectedSigner
+		}
+		if !tx.V.LtUint64(35) {
+			if tx.V.IsUint64() {
+				return signerForUint64((tx.V.Uint64() - 35) / 2)
+			}
+			return signerFromV(tx.V.ToBig())
+		}
+	}
+	if !tx.ChainID.IsZero() {
+		if tx.ChainID.IsUint64() {
+			return signerForUint64(tx.ChainID.Uint64())
+		}
+		return SignerForChainID(tx.ChainID.ToBig())
+	}
+	return defaultSigner.Load()
+}
+
+// From returns the sender of the tx, recovering it from the signature the first time it is called. See CustomTx.From.
+func (tx *Tx256) From() (common.Address, error) {
+	if tx.hasFrom {
+		return tx.from, nil
+	}
+	// when the sender cache is enabled look for the sender before doing the ecrecover
+	cache := senderCache.Load()
+	var hash common.Hash
+	if cache != nil {
+		if hash = tx.Hash(); hash == zeroHash {
+			// the tx cannot be encoded, so it cannot be cached either
+			cache = nil
+		} else if from, ok := cache.Get(hash); ok {
+			tx.from, tx.hasFrom = from, true
+			return from, nil
+		}
+	}
+	from, err := tx.recoverSender()
+	if err != nil {
+		return common.Address{}, err
+	}
+	tx.from, tx.hasFrom = from, true
+	if cache != nil {
+		cache.Add(hash, from)
+	}
+	return from, nil
+}
+
+func (tx *Tx256) recoverSender() (common.Address, error) {
+	V, err := tx.recoveryID()
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !validateSignatureValuesUint256(V, &tx.R, &tx.S, false) {
+		return common.Address{}, errors.ErrInvalidSig
+	}
+	// encode the signature in uncompressed format
+	var sig [crypto.SignatureLength]byte
+	r, s := tx.R.Bytes32(), tx.S.Bytes32()
+	copy(sig[:32], r[:])
+	copy(sig[32:64], s[:])
+	sig[64] = V
+	h := tx.UnsignedHash()
+	// recover the public key from the signature
+	pub, err := crypto.Ecrecover(h[:], sig[:])
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(pub) == 0 || pub[0] != 4 {
+		return common.Address{}, errors.ErrInvalidPkb
+	}
+	return common.BytesToAddress(pubkeyToAddressBytes(pub)), nil
+}
+
+// recoveryID returns the recovery id of the signature, which is V for typed txs
+func (tx *Tx256) recoveryID() (byte, error) {
+	switch tx.TxType {
+	case types.LegacyTxType:
+		var base uint256.Int
+		if signer := tx.Signer(); signer.Protected() {
+			// EIP-155: V = recoveryId + 35 + chainId * 2
+			base.AddUint64(&signer.vMultiplier256, 35)
+		} else {
+			// pre EIP-155: V = recoveryId + 27
+			base.SetUint64(27)
+		}
+		if tx.V.Lt(&base) {
+			return 0, errors.ErrInvalidSig
+		}
+		var v uint256.Int
+		v.Sub(&tx.V, &base)
+		if !v.LtUint64(2) {
+			return 0, errors.ErrInvalidSig
+		}
+		return byte(v.Uint64()), nil
+	case types.DynamicFeeTxType, types.AccessListTxType, types.BlobTxType, types.SetCodeTxType:
+		if !tx.V.LtUint64(2) {
+			return 0, errors.ErrInvalidSig
+		}
+		return byte(tx.V.Uint64()), nil
+	default:
+		return 0, errors.ErrTxTypeNotSupported
+	}
+}
+
+// SignTx signs the tx with the key, setting its V, R and S values. Typed txs without chain id take the one of their
+// signer. The SignedRlpBytes, hash and sender of the tx are cleared.
+func (tx *Tx256) SignTx(key *ecdsa.PrivateKey) error {
+	if tx.TxType != types.LegacyTxType && tx.ChainID.IsZero() && tx.signer != nil && tx.signer.Protected() {
+		tx.ChainID.SetFromBig(tx.signer.chainId)
+		tx.hasUnsignedHash = false
+	}
+	h := tx.UnsignedHash()
+	if h == zeroHash {
+		return errors.ErrTxTypeNotSupported
+	}
+	sig, err := crypto.Sign(h[:], key)
+	if err != nil {
+		return err
+	}
+	// the unsigned hash is kept, it does not depend on the signature
+	tx.SignedRlpBytes = nil
+	tx.hasSignedHash = false
+	tx.hasFrom = false
+	tx.R.SetBytes(sig[:32])
+	tx.S.SetBytes(sig[32:64])
+	switch tx.TxType {
+	case types.LegacyTxType:
+		if signer := tx.Signer(); signer.Protected() {
+			tx.V.AddUint64(&signer.vMultiplier256, uint64(sig[64])+35)
+		} else {
+			tx.V.SetUint64(uint64(sig[64]) + 27)
+		}
+	default:
+		tx.V.SetUint64(uint64(sig[64]))
+	}
+	return nil
+}
+
+// ResetSignedVals clears the signature values, the SignedRlpBytes and the cached hashes and sender, see
+// CustomTx.ResetSignedVals
+func (tx *Tx256) ResetSignedVals() {
+	tx.V.Clear()
+	tx.R.Clear()
+	tx.S.Clear()
+	tx.SignedRlpBytes = nil
+	tx.hasSignedHash = false
+	tx.hasUnsignedHash = false
+	tx.hasFrom = false
+}
+
+// ToCustomTx converts the tx into a CustomTx, keeping its SignedRlpBytes, signer and the hash and sender already
+// known. Integers the tx type does not have are left nil.
+func (tx *Tx256) ToCustomTx() (*CustomTx, error) {
+	layout, ok := txLayouts[tx.TxType]
+	if !ok {
+		return nil, errors.ErrTxTypeNotSupported
+	}
+	c := &CustomTx{
+		TxType:             tx.TxType,
+		Nonce:              tx.Nonce,
+		Gas:                tx.Gas,
+		To:                 tx.To,
+		Data:               tx.Data,
+		AccessList:         tx.AccessList,
+		BlobHashes:         tx.BlobHashes,
+		Sidecar:            tx.Sidecar,
+		AuthList:           tx.AuthList,
+		SignedRlpBytes:     tx.SignedRlpBytes,
+		startTx:            tx.startTx,
+		startTxDataPointer: tx.startTxDataPointer,
+		startTxSignature:   tx.startTxSignature,
+		startBlobTxPayload: tx.startBlobTxPayload,
+		endBlobTxPayload:   tx.endBlobTxPayload,
+		signer:             tx.signer,
+	}
+	for _, field := range layout {
+		switch field {
+		case fieldChainID:
+			c.ChainID = tx.ChainID.ToBig()
+		case fieldGasPrice:
+			c.GasPrice = tx.GasPrice.ToBig()
+		case fieldGasTipCap:
+			c.GasTipCap = tx.GasTipCap.ToBig()
+		case fieldGasFeeCap:
+			c.GasFeeCap = tx.GasFeeCap.ToBig()
+		case fieldValue:
+			c.Value = tx.Value.ToBig()
+		case fieldBlobFeeCap:
+			c.BlobFeeCap = tx.BlobFeeCap.ToBig()
+		case fieldV:
+			c.V = tx.V.ToBig()
+		case fieldR:
+			c.R = tx.R.ToBig()
+		case fieldS:
+			c.S = tx.S.ToBig()
+		}
+	}
+	if len(tx.SignedRlpBytes) > 0 {
+		c.rlpSignedBytesLength = len(tx.SignedRlpBytes)
+	}
+	if tx.hasSignedHash {
+		c.signedHash = common.CopyBytes(tx.signedHash[:])
+	}
+	if tx.hasUnsignedHash {
+		c.unsignedHash = common.CopyBytes(tx.unsignedHash[:])
+	}
+	if tx.hasFrom {
+		c.from = common.CopyBytes(tx.from[:])
+	}
+	return c, nil
+}
+
+// FromCustomTx sets the tx to the values of c, keeping its SignedRlpBytes, signer and the hash and sender already
+// known. Nil integers are converted into zero, integers that do not fit in 256 bits are rejected.
+func (tx *Tx256) FromCustomTx(c *CustomTx) error {
+	t := Tx256{
+		TxType:             c.TxType,
+		Nonce:              c.Nonce,
+		Gas:                c.Gas,
+		To:                 c.To,
+		Data:               c.Data,
+		AccessList:         c.AccessList,
+		BlobHashes:         c.BlobHashes,
+		Sidecar:            c.Sidecar,
+		AuthList:           c.AuthList,
+		SignedRlpBytes:     c.SignedRlpBytes,
+		startTx:            c.startTx,
+		startTxDataPointer: c.startTxDataPointer,
+		startTxSignature:   c.startTxSignature,
+		startBlobTxPayload: c.startBlobTxPayload,
+		endBlobTxPayload:   c.endBlobTxPayload,
+		signer:             c.signer,
+	}
+	for field, v := range map[txField]*big.Int{fieldChainID: c.ChainID, fieldGasPrice: c.GasPrice,
+		fieldGasTipCap: c.GasTipCap, fieldGasFeeCap: c.GasFeeCap, fieldValue: c.Value, fieldBlobFeeCap: c.BlobFeeCap,
+		fieldV: c.V, fieldR: c.R, fieldS: c.S} {
+		if v != nil && t.uint256Field(field).SetFromBig(v) {
+			err := errors.ErrValueNotSupport.WithMessagef("value %s overflows 256 bits", v)
+			return errors.WithField(err, txFieldNames[field])
+		}
+	}
+	if len(c.signedHash) > 0 {
+		t.signedHash, t.hasSignedHash = common.BytesToHash(c.signedHash), true
+	}
+	if len(c.unsignedHash) > 0 {
+		t.unsignedHash, t.hasUnsignedHash = common.BytesToHash(c.unsignedHash), true
+	}
+	if len(c.from) > 0 {
+		t.from, t.hasFrom = common.BytesToAddress(c.from), true
+	}
+	*tx = t
+	return nil
+}
+
+// ToTx converts the tx into a go-ethereum transaction, see CustomTx.ToTx
+func (tx *Tx256) ToTx() (*types.Transaction, error) {
+	c, err := tx.ToCustomTx()
+	if err != nil {
+		return nil, err
+	}
+	return c.ToTx()
+}
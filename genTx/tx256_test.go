@@ -0,0 +1,373 @@
+package genTx
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+// tx256TxsForTests returns txs of every type: the fuzz seeds, an unprotected legacy tx and a blob tx in its network
+// form
+func tx256TxsForTests(t testing.TB) []*types.Transaction {
+	privKey, err := getPrivkeyForTests()
+	assert.NoError(t, err)
+	unprotected, err := types.SignNewTx(privKey, types.HomesteadSigner{},
+		&types.LegacyTx{Nonce: 6, GasPrice: big.NewInt(1e9), Gas: 53000, Value: big.NewInt(1e18), Data: []byte{0x60}})
+	assert.NoError(t, err)
+	txs := append(fuzzSeedTxsForTests(t), unprotected, newSignedBlobTxForTests(t, 7))
+	// decoded by go-ethereum, so their fields are the ones compared by compareTxFieldsForTests
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(t, err)
+	assert.NoError(t, rlp.DecodeBytes(packet, &txs))
+	return txs
+}
+
+// senderForTests returns the sender of the tx recovered by go-ethereum
+func senderForTests(t testing.TB, tx *types.Transaction) common.Address {
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.LatestSignerForChainID(tx.ChainId())
+	}
+	from, err := types.Sender(signer, tx)
+	assert.NoError(t, err)
+	return from
+}
+
+// compareTx256ForTests checks the fields of the tx against the go-ethereum tx
+func compareTx256ForTests(t *testing.T, got *Tx256, want *types.Transaction) {
+	u := func(v *big.Int) (res uint256.Int) {
+		if v != nil {
+			res.SetFromBig(v)
+		}
+		return res
+	}
+	assert.Equal(t, want.Type(), got.TxType)
+	assert.Equal(t, want.Nonce(), got.Nonce)
+	assert.Equal(t, want.Gas(), got.Gas)
+	assert.Equal(t, want.To(), got.To)
+	assert.Equal(t, want.Value().Bytes(), got.Value.Bytes())
+	assert.True(t, bytes.Equal(want.Data(), got.Data))
+	v, r, s := want.RawSignatureValues()
+	assert.Equal(t, u(v), got.V)
+	assert.Equal(t, u(r), got.R)
+	assert.Equal(t, u(s), got.S)
+	switch want.Type() {
+	case types.LegacyTxType, types.AccessListTxType:
+		assert.Equal(t, u(want.GasPrice()), got.GasPrice)
+	default:
+		assert.Equal(t, u(want.GasTipCap()), got.GasTipCap)
+		assert.Equal(t, u(want.GasFeeCap()), got.GasFeeCap)
+	}
+	if want.Type() != types.LegacyTxType {
+		assert.Equal(t, u(want.ChainId()), got.ChainID)
+		assert.Equal(t, len(want.AccessList()), len(got.AccessList))
+	}
+	assert.Equal(t, u(want.BlobGasFeeCap()), got.BlobFeeCap)
+	assert.Equal(t, want.BlobHashes(), got.BlobHashes)
+	assert.Equal(t, want.BlobTxSidecar(), got.Sidecar)
+	assert.Equal(t, len(want.SetCodeAuthorizations()), len(got.AuthList))
+}
+
+func TestDecodeTxsPacket256(t *testing.T) {
+	txs := tx256TxsForTests(t)
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(t, err)
+	got, err := DecodeTxsPacket256(reader.NewStrictReader(packet))
+	assert.NoError(t, err)
+	assert.Len(t, got, len(txs))
+	customTxs, err := DecodeTxsPacket(reader.NewReader(packet))
+	assert.NoError(t, err)
+
+	for i, tx := range got {
+		want := txs[i]
+		compareTx256ForTests(t, tx, want)
+		assert.Equal(t, want.Hash(), tx.Hash())
+		from, err := tx.From()
+		assert.NoError(t, err)
+		wantFrom := senderForTests(t, want)
+		assert.Equal(t, wantFrom, from)
+		assert.Equal(t, customTxs[i].UnsignedHash(), tx.UnsignedHash())
+
+		// the same bytes are encoded again without the SignedRlpBytes
+		enc, err := rlp.EncodeToBytes(want)
+		assert.NoError(t, err)
+		assert.Equal(t, enc, tx.SignedRlpBytes)
+		encoded := *tx
+		encoded.ResetSignedVals()
+		encoded.V, encoded.R, encoded.S = tx.V, tx.R, tx.S
+		length, err := encoded.SignedRLPLength()
+		assert.NoError(t, err)
+		assert.Equal(t, len(enc), length)
+		// the unsigned hash is encoded from the fields too
+		assert.Equal(t, tx.UnsignedHash(), encoded.UnsignedHash())
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, encoded.EncodeSignedRLP(buffer, true))
+		assert.Equal(t, enc, buffer.Bytes())
+		assert.Equal(t, want.Hash(), encoded.Hash())
+
+		// conversion into CustomTx
+		c, err := tx.ToCustomTx()
+		assert.NoError(t, err)
+		compareTxFieldsForTests(t, c, want)
+		assert.Equal(t, want.Hash(), c.Hash())
+		cFrom, err := c.From()
+		assert.NoError(t, err)
+		assert.Equal(t, wantFrom, cFrom)
+		var back Tx256
+		assert.NoError(t, back.FromCustomTx(customTxs[i]))
+		compareTx256ForTests(t, &back, want)
+		assert.Equal(t, want.Hash(), back.Hash())
+	}
+
+	buffer := new(bytes.Buffer)
+	assert.NoError(t, EncodeTxsPacket256(buffer, got))
+	assert.Equal(t, packet, buffer.Bytes())
+}
+
+func TestDecodeTx256_Errors(t *testing.T) {
+	txs := fuzzSeedTxsForTests(t)
+	enc, err := rlp.EncodeToBytes(txs[2])
+	assert.NoError(t, err)
+	// the txs DecodeTx rejects are rejected too
+	for _, b := range [][]byte{enc[:len(enc)-1], {0xc0}, {0x80}, rlpListForTests(enc, enc)} {
+		_, wantErr := DecodeTx(reader.NewReader(b))
+		assert.Error(t, wantErr)
+		_, err = DecodeTx256(reader.NewReader(b))
+		assert.Error(t, err, "%x", b)
+	}
+
+	// integers must fit in 256 bits
+	tooBig := append([]byte{0xa1, 0x01}, make([]byte, 32)...)
+	b := rlpListForTests([]byte{0x01}, tooBig, []byte{0x01}, []byte{0x80}, []byte{0x80}, []byte{0x80}, []byte{0x25},
+		[]byte{0x01}, []byte{0x01})
+	_, err = DecodeTx256(reader.NewReader(b))
+	assert.ErrorIs(t, err, errors.ErrUnexpectedLength)
+	assert.Equal(t, "gasPrice", errors.GetPosition(err).Field)
+
+	var tx Tx256
+	err = tx.FromCustomTx(&CustomTx{Value: new(big.Int).Lsh(big.NewInt(1), 256)})
+	assert.ErrorIs(t, err, errors.ErrValueNotSupport)
+}
+
+func TestTx256_SignTx(t *testing.T) {
+	privKey, err := getPrivkeyForTests()
+	assert.NoError(t, err)
+	wantFrom := crypto.PubkeyToAddress(privKey.PublicKey)
+	for _, want := range tx256TxsForTests(t) {
+		tx, err := DecodeTx256(reader.NewReader(mustEncodeForTests(t, want)))
+		assert.NoError(t, err)
+		unsignedHash := tx.UnsignedHash()
+		tx.ResetSignedVals()
+		if want.Protected() {
+			tx.SetSigner(SignerForChainID(want.ChainId()))
+		} else {
+			tx.SetSigner(NewUnprotectedSigner())
+		}
+		assert.NoError(t, tx.SignTx(privKey))
+		assert.Equal(t, unsignedHash, tx.UnsignedHash())
+		// signatures are deterministic, so the tx is the same as the one signed by go-ethereum
+		assert.Equal(t, want.Hash(), tx.Hash())
+		compareTx256ForTests(t, tx, want)
+		from, err := tx.From()
+		assert.NoError(t, err)
+		assert.Equal(t, wantFrom, from)
+	}
+
+	// typed txs take the chain id of their signer
+	tx := &Tx256{TxType: types.DynamicFeeTxType, Nonce: 1, Gas: 21000}
+	tx.SetSigner(SignerForChainID(big.NewInt(56)))
+	assert.NoError(t, tx.SignTx(privKey))
+	assert.Equal(t, uint64(56), tx.ChainID.Uint64())
+	normalTx, err := tx.ToTx()
+	assert.NoError(t, err)
+	from, err := types.Sender(types.LatestSignerForChainID(big.NewInt(56)), normalTx)
+	assert.NoError(t, err)
+	assert.Equal(t, wantFrom, from)
+}
+
+func TestTx256_From_InvalidSig(t *testing.T) {
+	tx, err := DecodeTx256(reader.NewReader(mustEncodeForTests(t, fuzzSeedTxsForTests(t)[2])))
+	assert.NoError(t, err)
+	tx.V.SetUint64(2)
+	_, err = tx.From()
+	assert.ErrorIs(t, err, errors.ErrInvalidSig)
+
+	tx, err = DecodeTx256(reader.NewReader(mustEncodeForTests(t, fuzzSeedTxsForTests(t)[0])))
+	assert.NoError(t, err)
+	// recovery id 3 for chain 1
+	tx.SetSigner(SignerForChainID(big.NewInt(1)))
+	tx.V.SetUint64(40)
+	_, err = tx.From()
+	assert.ErrorIs(t, err, errors.ErrInvalidSig)
+}
+
+func TestTx256_JSON(t *testing.T) {
+	for _, want := range fuzzSeedTxsForTests(t) {
+		enc := mustEncodeForTests(t, want)
+		tx, err := DecodeTx256(reader.NewReader(enc))
+		assert.NoError(t, err)
+		_, err = tx.From()
+		assert.NoError(t, err)
+		b, err := json.Marshal(tx)
+		assert.NoError(t, err)
+		// the same json as CustomTx
+		c, err := DecodeTx(reader.NewReader(enc))
+		assert.NoError(t, err)
+		_, err = c.From()
+		assert.NoError(t, err)
+		wantJSON, err := c.MarshalJson()
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(wantJSON), string(b))
+
+		var got Tx256
+		assert.NoError(t, json.Unmarshal(b, &got))
+		from, err := got.From()
+		assert.NoError(t, err)
+		assert.Equal(t, tx.from, from)
+		assert.Equal(t, tx.Value, got.Value)
+		assert.Equal(t, tx.V, got.V)
+		assert.Equal(t, tx.R, got.R)
+		assert.Equal(t, tx.S, got.S)
+		assert.Equal(t, tx.GasPrice, got.GasPrice)
+		assert.Equal(t, tx.GasTipCap, got.GasTipCap)
+		assert.Equal(t, tx.GasFeeCap, got.GasFeeCap)
+		assert.Equal(t, tx.ChainID, got.ChainID)
+	}
+}
+
+func TestDecodeTxsPacket256Into(t *testing.T) {
+	txs := fuzzSeedTxsForTests(t)
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(t, err)
+	r := reader.NewReader(packet)
+	got, err := DecodeTxsPacket256Into(r, nil)
+	assert.NoError(t, err)
+	for i, tx := range got {
+		compareTx256ForTests(t, tx, txs[i])
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		r.Reset(packet)
+		if got, err = DecodeTxsPacket256Into(r, got); err != nil {
+			t.Fatal(err)
+		}
+	})
+	assert.Zero(t, allocs)
+	for i, tx := range got {
+		compareTx256ForTests(t, tx, txs[i])
+		assert.Equal(t, txs[i].Hash(), tx.Hash())
+	}
+}
+
+func mustEncodeForTests(t testing.TB, tx *types.Transaction) []byte {
+	enc, err := rlp.EncodeToBytes(tx)
+	assert.NoError(t, err)
+	return enc
+}
+
+func BenchmarkDecodeTxsPacket256(b *testing.B) {
+	packet, err := rlp.EncodeToBytes(fuzzSeedTxsForTests(b))
+	assert.NoError(b, err)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err = DecodeTxsPacket256(reader.NewReader(packet)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeTxsPacket256Into(b *testing.B) {
+	packet, err := rlp.EncodeToBytes(fuzzSeedTxsForTests(b))
+	assert.NoError(b, err)
+	var txs []*Tx256
+	r := reader.NewReader(packet)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.Reset(packet)
+		if txs, err = DecodeTxsPacket256Into(r, txs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// encodeBenchTxsForTests returns the txs decoded as CustomTx and as Tx256 without their SignedRlpBytes, so encoding
+// them writes every field
+func encodeBenchTxsForTests(b *testing.B) ([]*CustomTx, []*Tx256) {
+	packet, err := rlp.EncodeToBytes(fuzzSeedTxsForTests(b))
+	assert.NoError(b, err)
+	customTxs, err := DecodeTxsPacket(reader.NewReader(packet))
+	assert.NoError(b, err)
+	txs256, err := DecodeTxsPacket256(reader.NewReader(packet))
+	assert.NoError(b, err)
+	for i := range customTxs {
+		customTxs[i].SignedRlpBytes = nil
+		txs256[i].SignedRlpBytes = nil
+	}
+	return customTxs, txs256
+}
+
+func BenchmarkEncodeTxsPacket(b *testing.B) {
+	customTxs, _ := encodeBenchTxsForTests(b)
+	buffer := new(bytes.Buffer)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buffer.Reset()
+		if err := EncodeTxsPacket(buffer, customTxs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeTxsPacket256(b *testing.B) {
+	_, txs256 := encodeBenchTxsForTests(b)
+	buffer := new(bytes.Buffer)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buffer.Reset()
+		if err := EncodeTxsPacket256(buffer, txs256); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCustomTx_From(b *testing.B) {
+	packet, err := rlp.EncodeToBytes(fuzzSeedTxsForTests(b))
+	assert.NoError(b, err)
+	txs, err := DecodeTxsPacket(reader.NewReader(packet))
+	assert.NoError(b, err)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, tx := range txs {
+			tx.from = nil
+			tx.unsignedHash = nil
+			if _, err = tx.From(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkTx256_From(b *testing.B) {
+	packet, err := rlp.EncodeToBytes(fuzzSeedTxsForTests(b))
+	assert.NoError(b, err)
+	txs, err := DecodeTxsPacket256(reader.NewReader(packet))
+	assert.NoError(b, err)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, tx := range txs {
+			tx.hasFrom = false
+			tx.hasUnsignedHash = false
+			if _, err = tx.From(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -494,3 +494,17 @@ func compareDynamicFeesTx(t *testing.T, got *CustomTx, want *types.Transaction)
 		}
 	}
 }
+func compareBlobTx(t *testing.T, got *CustomTx, want *types.Transaction) {
+	compareDynamicFeesTx(t, got, want)
+	assert.Equal(t, want.BlobGasFeeCap().String(), got.BlobFeeCap.String(), "blob fee cap not equal")
+	assert.Equal(t, want.BlobHashes(), got.BlobHashes, "blob hashes not equal")
+	if want.BlobTxSidecar() != nil {
+		assert.Equal(t, want.BlobTxSidecar(), got.Sidecar, "sidecar not equal")
+	} else {
+		assert.Nil(t, got.Sidecar)
+	}
+}
+func compareSetCodeTx(t *testing.T, got *CustomTx, want *types.Transaction) {
+	compareDynamicFeesTx(t, got, want)
+	assert.Equal(t, want.SetCodeAuthorizations(), got.AuthList, "auth list not equal")
+}
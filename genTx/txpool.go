@@ -0,0 +1,26 @@
+package genTx
+
+import "github.com/1aBcD1234aBcD1/prlp/pool"
+
+// customTxs pools the txs, keeping the allocations of their values, see CustomTx.Reset
+var customTxs = pool.NewPool(func() *CustomTx { return new(CustomTx) }, (*CustomTx).Reset)
+
+// GetCustomTx returns a tx from the pool, ready to be decoded into with DecodeTxInto
+func GetCustomTx() *CustomTx {
+	return customTxs.Get()
+}
+
+// PutCustomTx resets the tx and puts it back in the pool. Neither the tx nor the values taken from it can be used
+// after putting it back.
+func PutCustomTx(tx *CustomTx) {
+	customTxs.Put(tx)
+}
+
+// PutCustomTxs puts every tx back in the pool, see PutCustomTx
+func PutCustomTxs(txs []*CustomTx) {
+	for _, tx := range txs {
+		if tx != nil {
+			customTxs.Put(tx)
+		}
+	}
+}
@@ -1,8 +1,25 @@
 package genTx
 
+// RLPValue is the kind of an RLP value
 type RLPValue int
 
 const (
 	RlpString RLPValue = iota
-	RL
+	RlpList
 )
+
+// RL is the former name of RlpList.
+//
+// Deprecated: use RlpList
+const RL = RlpList
+
+func (v RLPValue) String() string {
+	switch v {
+	case RlpString:
+		return "string"
+	case RlpList:
+		return "list"
+	default:
+		return "unknown"
+	}
+}
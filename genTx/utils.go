@@ -4,6 +4,7 @@ import (
 	"github.com/1aBcD1234aBcD1/prlp/errors"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
 	"math/big"
 	"math/bits"
 )
@@ -57,8 +58,21 @@ func CalculateRLBigIntValueLength(val *big.Int) int {
 	}
 }
 
+func CalculateRLUint256ValueLength(val *uint256.Int) int {
+	switch valueLength := val.ByteLen(); {
+	case valueLength == 0:
+		return 1
+	case valueLength == 1 && val.Uint64() <= 0x7f:
+		return 1
+	default:
+		return 1 + valueLength
+	}
+}
+
 func CalculateRLPBytesLength(data []byte) int {
 	switch valueLength := len(data); {
+	case valueLength == 1 && data[0] <= 0x7f:
+		return 1
 	case valueLength < 56:
 		return 1 + valueLength
 	default:
@@ -135,3 +149,15 @@ func validateSignatureValues(v byte, r, s *big.Int, homestead bool) bool {
 	// Frontier: allow s to be in full N range
 	return r.Cmp(secp256k1N) < 0 && s.Cmp(secp256k1N) < 0 && (v == 0 || v == 1)
 }
+
+// validateSignatureValuesUint256 is the same as validateSignatureValues but avoids converting uint256 values into big.Int
+func validateSignatureValuesUint256(v byte, r, s *uint256.Int, homestead bool) bool {
+	if r.IsZero() || s.IsZero() {
+		return false
+	}
+	// reject upper range of s values (ECDSA malleability)
+	if homestead && s.Gt(secp256k1halfNU256) {
+		return false
+	}
+	return r.Lt(secp256k1NU256) && s.Lt(secp256k1NU256) && (v == 0 || v == 1)
+}
@@ -2,162 +2,115 @@ package genTx
 
 import (
 	"bytes"
+	"github.com/holiman/uint256"
 )
 
 var ZeroUint64RLPVal = byte(0x80)
 var ZeroListRLPVal = byte(0xc0)
 
+// maxHeaderLength is the length of the largest rlp header: the prefix byte and a size of up to 8 bytes
+const maxHeaderLength = 9
+
 func WriteValLength(buffer *bytes.Buffer, length int) (int, error) {
-	switch {
-	case length <= 56:
-		{
-			buffer.WriteByte(0x80 + byte(length))
-			return 1, nil
-		}
-	default:
-		{
-			// calculate how many bytes are needed to represent the length
-			bLength := IntUnsignedLength(length)
-			buffer.WriteByte(0xb7 + byte(bLength))
-			_, err := WriteUint64(buffer, uint64(length))
-			return bLength + 1, err
-		}
-	}
+	var header [maxHeaderLength]byte
+	return buffer.Write(AppendValLength(header[:0], length))
 }
 
 func WriteListLength(buffer *bytes.Buffer, length int) (int, error) {
-	switch {
-	case length < 56:
-		{
-			buffer.WriteByte(0xC0 + byte(length))
-			return 1, nil
-		}
-	default:
-		{
-			// calculate how many bytes are needed to represent the length
-			bLength := IntUnsignedLength(length)
-			buffer.WriteByte(0xf7 + byte(bLength))
-			_, err := WriteUint64(buffer, uint64(length))
-			return bLength + 1, err
-		}
-	}
+	var header [maxHeaderLength]byte
+	return buffer.Write(AppendListLength(header[:0], length))
 }
 
 func WriteRLPBytes(b *bytes.Buffer, data []byte) error {
-	if len(data) == 0 {
-		return b.WriteByte(ZeroUint64RLPVal)
-	}
-
-	var (
-		err error
-	)
-	bytesLength := len(data)
-	switch {
-	case bytesLength == 1 && data[0] <= 0x7f:
+	if len(data) == 1 && data[0] <= 0x7f {
 		return b.WriteByte(data[0])
-	case bytesLength < 56:
-		err = b.WriteByte(0x80 + byte(bytesLength))
-	default:
-
-		err = b.WriteByte(0xb7 + byte(IntUnsignedLength(bytesLength)))
-		if err != nil {
-			return err
-		}
-		_, err = WriteUint64(b, uint64(bytesLength))
 	}
+	_, err := WriteValLength(b, len(data))
 	if err != nil {
 		return err
 	}
 	_, err = b.Write(data)
 	return err
-
 }
 
 func WriteRLPUint64(b *bytes.Buffer, i uint64) error {
-	if i == 0 {
-		return b.WriteByte(ZeroUint64RLPVal)
-	}
-
-	bytesLength := Uint64Length(i)
-	var (
-		err error
-	)
-	switch {
-	case bytesLength == 1 && i <= 0x7f:
-		return b.WriteByte(byte(i))
-	case bytesLength < 56:
-		err = b.WriteByte(0x80 + byte(bytesLength))
-	default:
-		err = b.WriteByte(0xb7 + byte(bytesLength))
-		if err != nil {
-			return err
-		}
-		_, err = WriteUint64(b, uint64(bytesLength))
-	}
-	if err != nil {
-		return err
-	}
-
-	_, err = WriteUint64(b, i)
+	var val [maxHeaderLength]byte
+	_, err := b.Write(AppendRLPUint64(val[:0], i))
 	return err
+}
 
+// WriteRLPUint256 writes the rlp of i, the same as WriteRLPBytes(b, i.Bytes()) but without allocating
+func WriteRLPUint256(b *bytes.Buffer, i *uint256.Int) error {
+	var val [33]byte
+	_, err := b.Write(AppendRLPUint256(val[:0], i))
+	return err
 }
 
 // WriteUint64 writes i to the beginning of b in big endian byte
 // order, using the least number of bytes needed to represent i.
 func WriteUint64(b *bytes.Buffer, i uint64) (int, error) {
+	var val [8]byte
+	return b.Write(AppendUint64(val[:0], i))
+}
+
+// AppendValLength appends the header of an rlp string of the length to dst and returns the extended slice
+func AppendValLength(dst []byte, length int) []byte {
+	if length < 56 {
+		return append(dst, 0x80+byte(length))
+	}
+	// calculate how many bytes are needed to represent the length
+	dst = append(dst, 0xb7+byte(IntUnsignedLength(length)))
+	return AppendUint64(dst, uint64(length))
+}
+
+// AppendListLength appends the header of an rlp list whose items take length bytes to dst and returns the extended
+// slice
+func AppendListLength(dst []byte, length int) []byte {
+	if length < 56 {
+		return append(dst, 0xC0+byte(length))
+	}
+	dst = append(dst, 0xf7+byte(IntUnsignedLength(length)))
+	return AppendUint64(dst, uint64(length))
+}
+
+// AppendRLPBytes appends the rlp string of data to dst and returns the extended slice
+func AppendRLPBytes(dst []byte, data []byte) []byte {
+	if len(data) == 1 && data[0] <= 0x7f {
+		return append(dst, data[0])
+	}
+	return append(AppendValLength(dst, len(data)), data...)
+}
+
+// AppendRLPUint64 appends the rlp of i to dst and returns the extended slice
+func AppendRLPUint64(dst []byte, i uint64) []byte {
 	switch {
 	case i == 0:
-		return 0, nil
-	case i < (1 << 8):
-		return 1, b.WriteByte(byte(i))
-	case i < (1 << 16):
-		return b.Write([]byte{byte(i >> 8), byte(i)})
-	case i < (1 << 24):
-		return b.Write([]byte{
-			byte(i >> 16),
-			byte(i >> 8),
-			byte(i)})
-	case i < (1 << 32):
-		return b.Write([]byte{
-			byte(i >> 24),
-			byte(i >> 16),
-			byte(i >> 8),
-			byte(i)})
-	case i < (1 << 40):
-		return b.Write([]byte{
-			byte(i >> 32),
-			byte(i >> 24),
-			byte(i >> 16),
-			byte(i >> 8),
-			byte(i)})
-	case i < (1 << 48):
-		return b.Write([]byte{
-			byte(i >> 40),
-			byte(i >> 32),
-			byte(i >> 24),
-			byte(i >> 16),
-			byte(i >> 8),
-			byte(i)})
-	case i < (1 << 56):
-		return b.Write([]byte{
-			byte(i >> 48),
-			byte(i >> 40),
-			byte(i >> 32),
-			byte(i >> 24),
-			byte(i >> 16),
-			byte(i >> 8),
-			byte(i)})
-	default:
-		return b.Write([]byte{
-			byte(i >> 56),
-			byte(i >> 48),
-			byte(i >> 40),
-			byte(i >> 32),
-			byte(i >> 24),
-			byte(i >> 16),
-			byte(i >> 8),
-			byte(i)})
+		return append(dst, ZeroUint64RLPVal)
+	case i <= 0x7f:
+		return append(dst, byte(i))
 	}
+	dst = append(dst, 0x80+byte(Uint64Length(i)))
+	return AppendUint64(dst, i)
+}
 
+// AppendRLPUint256 appends the rlp of i to dst and returns the extended slice
+func AppendRLPUint256(dst []byte, i *uint256.Int) []byte {
+	bytesLength := i.ByteLen()
+	switch {
+	case bytesLength == 0:
+		return append(dst, ZeroUint64RLPVal)
+	case bytesLength == 1 && i[0] <= 0x7f:
+		return append(dst, byte(i[0]))
+	}
+	val := i.Bytes32()
+	return append(append(dst, 0x80+byte(bytesLength)), val[32-bytesLength:]...)
+}
+
+// AppendUint64 appends i in big endian byte order to dst, using the least number of bytes needed to represent i, and
+// returns the extended slice. Nothing is appended for 0.
+func AppendUint64(dst []byte, i uint64) []byte {
+	for n := Uint64Length(i); n > 0 && i != 0; n-- {
+		dst = append(dst, byte(i>>(8*(n-1))))
+	}
+	return dst
 }
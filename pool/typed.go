@@ -0,0 +1,32 @@
+package pool
+
+import "sync"
+
+// Pool is a typed sync.Pool whose values are reset when they are put back
+type Pool[T any] struct {
+	pool  sync.Pool
+	reset func(T)
+}
+
+// NewPool creates a pool that allocates its values with newFn and resets them with reset when they are put back
+func NewPool[T any](newFn func() T, reset func(T)) *Pool[T] {
+	return &Pool[T]{
+		pool: sync.Pool{
+			New: func() interface{} {
+				return newFn()
+			},
+		},
+		reset: reset,
+	}
+}
+
+// Get returns a value of the pool, allocating it when the pool is empty
+func (p *Pool[T]) Get() T {
+	return p.pool.Get().(T)
+}
+
+// Put resets the value and puts it back in the pool
+func (p *Pool[T]) Put(v T) {
+	p.reset(v)
+	p.pool.Put(v)
+}
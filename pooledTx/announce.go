@@ -0,0 +1,238 @@
+package pooledTx
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/genTx"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math"
+)
+
+// Announcement is the eth/68 NewPooledTransactionHashes message, announcing the type, size and hash of txs. The
+// entries of the three lists at the same index belong to the same tx.
+type Announcement struct {
+	Types  []byte
+	Sizes  []uint32
+	Hashes []common.Hash
+}
+
+// NewAnnouncement builds the announcement of the txs
+func NewAnnouncement(txs []*genTx.CustomTx) (*Announcement, error) {
+	a := &Announcement{
+		Types:  make([]byte, 0, len(txs)),
+		Sizes:  make([]uint32, 0, len(txs)),
+		Hashes: make([]common.Hash, 0, len(txs)),
+	}
+	for _, tx := range txs {
+		if err := a.Add(tx); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// Add announces the tx, using its rlp bytes to get its size when it has them
+func (a *Announcement) Add(tx *genTx.CustomTx) error {
+	size, err := TxSize(tx)
+	if err != nil {
+		return err
+	}
+	a.Types = append(a.Types, tx.TxType)
+	a.Sizes = append(a.Sizes, size)
+	a.Hashes = append(a.Hashes, tx.Hash())
+	return nil
+}
+
+// Reset empties the announcement keeping its arrays, so it can be built or decoded into again
+func (a *Announcement) Reset() {
+	a.Types = a.Types[:0]
+	a.Sizes = a.Sizes[:0]
+	a.Hashes = a.Hashes[:0]
+}
+
+// Len returns the number of announced txs
+func (a *Announcement) Len() int {
+	return len(a.Hashes)
+}
+
+// TxSize returns the size announced for the tx, the length of its binary encoding: the rlp list of a legacy tx, the
+// type byte followed by the rlp list of the rest, and the network form of blob txs with a sidecar.
+func TxSize(tx *genTx.CustomTx) (uint32, error) {
+	length := len(tx.SignedRlpBytes)
+	if length == 0 {
+		l, _, err := tx.CalculateRLPSignedBytesLength()
+		if err != nil {
+			return 0, err
+		}
+		length = l
+	}
+	// typed txs are encoded as an rlp string holding their binary encoding
+	if tx.TxType != types.LegacyTxType {
+		length = stringPayloadLength(length)
+	}
+	if length > math.MaxUint32 {
+		return 0, errors.ErrValueNotSupport.WithMessagef("tx size %d does not fit in 32 bits", length)
+	}
+	return uint32(length), nil
+}
+
+// stringPayloadLength returns the length of the payload of an rlp string whose encoding has length bytes
+func stringPayloadLength(length int) int {
+	if length <= 56 {
+		return length - 1
+	}
+	for n := 1; ; n++ {
+		if payload := length - 1 - n; genTx.Uint64Length(uint64(payload)) == n {
+			return payload
+		}
+	}
+}
+
+// DecodeAnnouncement decodes a NewPooledTransactionHashes message. The announcement does not point to the decoded
+// bytes.
+func DecodeAnnouncement(r *reader.RlpReader) (*Announcement, error) {
+	a := new(Announcement)
+	if err := DecodeAnnouncementInto(r, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// DecodeAnnouncementInto is the same as DecodeAnnouncement but decoding into a, reusing its arrays, so decoding
+// announcements into the same one does not allocate once its arrays are large enough.
+// The three lists must have the same number of entries.
+func DecodeAnnouncementInto(r *reader.RlpReader, a *Announcement) error {
+	a.Reset()
+	start := r.Pos()
+	listSize, err := r.ReadListSize()
+	if err != nil {
+		return err
+	}
+	end := r.Pos() + listSize
+	txTypes, err := decodeString(r)
+	if err != nil {
+		return errors.WithField(err, "types")
+	}
+	// copied so appending to the announcement never writes into the decoded bytes
+	a.Types = append(a.Types, txTypes...)
+	if a.Sizes, err = decodeSizesInto(r, a.Sizes); err != nil {
+		return errors.WithField(err, "sizes")
+	}
+	if a.Hashes, err = decodeHashesInto(r, a.Hashes); err != nil {
+		return errors.WithField(err, "hashes")
+	}
+	if r.Pos() != end {
+		err = errors.ErrUnexpectedLength.WithMessagef("announcement ends at %d instead of %d", r.Pos(), end)
+		return errors.AtOffset(err, start)
+	}
+	if len(a.Types) != len(a.Hashes) || len(a.Sizes) != len(a.Hashes) {
+		err = errors.ErrUnexpectedLength.WithMessagef("announced %d types, %d sizes and %d hashes", len(a.Types), len(a.Sizes), len(a.Hashes))
+		return errors.AtOffset(err, start)
+	}
+	return nil
+}
+
+// decodeSizesInto decodes a list of sizes, reusing the array of dst
+func decodeSizesInto(r *reader.RlpReader, dst []uint32) ([]uint32, error) {
+	sizes := dst[:0]
+	listSize, err := r.ReadListSize()
+	if err != nil {
+		return sizes, err
+	}
+	cPos := r.Pos()
+	for r.Pos()-cPos < listSize {
+		start := r.Pos()
+		size, err := r.DecodeUint64()
+		if err != nil {
+			return sizes, errors.WithField(err, fmt.Sprintf("[%d]", len(sizes)))
+		}
+		if size > math.MaxUint32 {
+			err = errors.ErrUnexpectedLength.WithMessagef("size %d does not fit in 32 bits", size)
+			return sizes, errors.WithField(errors.AtOffset(err, start), fmt.Sprintf("[%d]", len(sizes)))
+		}
+		sizes = append(sizes, uint32(size))
+	}
+	if r.Pos() != cPos+listSize {
+		err = errors.ErrUnexpectedLength.WithMessagef("list items end at %d instead of %d", r.Pos(), cPos+listSize)
+		return sizes, errors.AtOffset(err, cPos)
+	}
+	return sizes, nil
+}
+
+// decodeHashesInto decodes a list of hashes, reusing the array of dst
+func decodeHashesInto(r *reader.RlpReader, dst []common.Hash) ([]common.Hash, error) {
+	hashes := dst[:0]
+	listSize, err := r.ReadListSize()
+	if err != nil {
+		return hashes, err
+	}
+	cPos := r.Pos()
+	for r.Pos()-cPos < listSize {
+		start := r.Pos()
+		h, err := decodeString(r)
+		if err != nil {
+			return hashes, errors.WithField(err, fmt.Sprintf("[%d]", len(hashes)))
+		}
+		if len(h) != common.HashLength {
+			err = errors.ErrUnexpectedLength.WithMessagef("hash length %d", len(h))
+			return hashes, errors.WithField(errors.AtOffset(err, start), fmt.Sprintf("[%d]", len(hashes)))
+		}
+		hashes = append(hashes, common.Hash(h))
+	}
+	if r.Pos() != cPos+listSize {
+		err = errors.ErrUnexpectedLength.WithMessagef("list items end at %d instead of %d", r.Pos(), cPos+listSize)
+		return hashes, errors.AtOffset(err, cPos)
+	}
+	return hashes, nil
+}
+
+// decodeString decodes the next value, which must not be a list
+func decodeString(r *reader.RlpReader) ([]byte, error) {
+	if r.IsNextValAList() {
+		return nil, errors.AtOffset(errors.ErrNotAString, r.Pos())
+	}
+	return r.DecodeNextValue()
+}
+
+// EncodeAnnouncement writes the announcement as a NewPooledTransactionHashes message
+func EncodeAnnouncement(buffer *bytes.Buffer, a *Announcement) error {
+	buffer.Grow(a.RLPLength())
+	_, err := buffer.Write(a.AppendRLP(buffer.AvailableBuffer()))
+	return err
+}
+
+// AppendRLP appends the announcement encoded as a NewPooledTransactionHashes message to dst and returns the extended
+// slice. dst is only reallocated when its capacity is not enough.
+func (a *Announcement) AppendRLP(dst []byte) []byte {
+	sizesLength := a.sizesLength()
+	hashesLength := len(a.Hashes) * genTx.HashRLPLength
+	dst = genTx.AppendListLength(dst, genTx.CalculateRLPBytesLength(a.Types)+
+		genTx.CalculateRLPListLength(sizesLength)+genTx.CalculateRLPListLength(hashesLength))
+	dst = genTx.AppendRLPBytes(dst, a.Types)
+	dst = genTx.AppendListLength(dst, sizesLength)
+	for _, size := range a.Sizes {
+		dst = genTx.AppendRLPUint64(dst, uint64(size))
+	}
+	dst = genTx.AppendListLength(dst, hashesLength)
+	for i := range a.Hashes {
+		dst = genTx.AppendRLPBytes(dst, a.Hashes[i][:])
+	}
+	return dst
+}
+
+// RLPLength returns the length of the announcement encoded as a NewPooledTransactionHashes message
+func (a *Announcement) RLPLength() int {
+	return genTx.CalculateRLPListLength(genTx.CalculateRLPBytesLength(a.Types) +
+		genTx.CalculateRLPListLength(a.sizesLength()) + genTx.CalculateRLPListLength(len(a.Hashes)*genTx.HashRLPLength))
+}
+
+func (a *Announcement) sizesLength() int {
+	var length int
+	for _, size := range a.Sizes {
+		length += genTx.CalculateRLP64ValueLength(uint64(size))
+	}
+	return length
+}
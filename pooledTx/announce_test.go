@@ -0,0 +1,221 @@
+package pooledTx
+
+import (
+	"bytes"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/genTx"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+// txsForTests returns signed txs of the announced types, of sizes using both forms of the rlp size headers
+func txsForTests(t testing.TB) []*types.Transaction {
+	privKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate private key: %v", err)
+	}
+	to := common.HexToAddress("0x2f2a2543b76a4166549f7aab2e75bef0aefc5b0f")
+	txsData := []types.TxData{
+		&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1e9), Gas: 21000, To: &to, Value: big.NewInt(1)},
+		&types.AccessListTx{ChainID: big.NewInt(8453), Nonce: 2, GasPrice: big.NewInt(1e9), Gas: 21000, To: &to,
+			AccessList: types.AccessList{{Address: to, StorageKeys: []common.Hash{{0x01}}}}},
+		&types.DynamicFeeTx{ChainID: big.NewInt(8453), Nonce: 3, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1e9),
+			Gas: 21000, To: &to, Data: make([]byte, 1<<16)},
+		&types.BlobTx{ChainID: uint256.NewInt(8453), Nonce: 4, GasTipCap: uint256.NewInt(1), GasFeeCap: uint256.NewInt(1e9),
+			Gas: 21000, To: to, BlobFeeCap: uint256.NewInt(1), BlobHashes: []common.Hash{{0x01}},
+			Sidecar: &types.BlobTxSidecar{
+				Blobs:       []kzg4844.Blob{{0x0a}},
+				Commitments: []kzg4844.Commitment{{0x01}},
+				Proofs:      []kzg4844.Proof{{0x02}},
+			}},
+	}
+	txs := make([]*types.Transaction, 0, len(txsData))
+	for _, txData := range txsData {
+		tx, err := types.SignNewTx(privKey, types.LatestSignerForChainID(big.NewInt(8453)), txData)
+		if err != nil {
+			t.Fatalf("Failed to sign tx: %v", err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+// announcementForTests returns the announcement geth sends for the txs
+func announcementForTests(txs []*types.Transaction) eth.NewPooledTransactionHashesPacket {
+	var packet eth.NewPooledTransactionHashesPacket
+	for _, tx := range txs {
+		packet.Types = append(packet.Types, tx.Type())
+		packet.Sizes = append(packet.Sizes, uint32(tx.Size()))
+		packet.Hashes = append(packet.Hashes, tx.Hash())
+	}
+	return packet
+}
+
+func TestNewAnnouncement(t *testing.T) {
+	txs := txsForTests(t)
+	want := announcementForTests(txs)
+	wantRLP, err := rlp.EncodeToBytes(&want)
+	assert.NoError(t, err)
+
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(t, err)
+	decoded, err := genTx.DecodeTxsPacket(reader.NewReader(packet))
+	assert.NoError(t, err)
+	// without rlp bytes, the sizes are calculated from the fields
+	fromFields := make([]*genTx.CustomTx, len(txs))
+	for i, tx := range txs {
+		fromFields[i] = new(genTx.CustomTx)
+		assert.NoError(t, fromFields[i].FromTx(tx))
+	}
+
+	for _, customTxs := range [][]*genTx.CustomTx{decoded, fromFields} {
+		a, err := NewAnnouncement(customTxs)
+		assert.NoError(t, err)
+		assert.Equal(t, want.Types, a.Types)
+		assert.Equal(t, want.Sizes, a.Sizes)
+		assert.Equal(t, want.Hashes, a.Hashes)
+
+		buffer := new(bytes.Buffer)
+		assert.NoError(t, EncodeAnnouncement(buffer, a))
+		assert.Equal(t, wantRLP, buffer.Bytes())
+		assert.Equal(t, len(wantRLP), a.RLPLength())
+		assert.Equal(t, append([]byte{0x01}, wantRLP...), a.AppendRLP([]byte{0x01}))
+	}
+
+	_, err = NewAnnouncement([]*genTx.CustomTx{{TxType: 0x7f}})
+	assert.ErrorIs(t, err, errors.ErrTxTypeNotSupported)
+}
+
+func TestDecodeAnnouncement(t *testing.T) {
+	txs := txsForTests(t)
+	for _, want := range []eth.NewPooledTransactionHashesPacket{
+		announcementForTests(txs),
+		announcementForTests(nil),
+		{Types: []byte{0x02}, Sizes: []uint32{1<<32 - 1}, Hashes: []common.Hash{{0x01}}},
+	} {
+		b, err := rlp.EncodeToBytes(&want)
+		assert.NoError(t, err)
+		r := reader.NewStrictReader(b)
+		a, err := DecodeAnnouncement(r)
+		assert.NoError(t, err)
+		assert.Zero(t, r.Len())
+		assert.Equal(t, len(want.Hashes), a.Len())
+		for i := range want.Hashes {
+			assert.Equal(t, want.Types[i], a.Types[i])
+			assert.Equal(t, want.Sizes[i], a.Sizes[i])
+			assert.Equal(t, want.Hashes[i], a.Hashes[i])
+		}
+		// encoded back as it was
+		assert.Equal(t, b, a.AppendRLP(nil))
+	}
+}
+
+func TestDecodeAnnouncementInto(t *testing.T) {
+	want := announcementForTests(txsForTests(t))
+	b, err := rlp.EncodeToBytes(&want)
+	assert.NoError(t, err)
+
+	a := new(Announcement)
+	r := reader.NewReader(b)
+	allocs := testing.AllocsPerRun(10, func() {
+		r.Reset(b)
+		if err := DecodeAnnouncementInto(r, a); err != nil {
+			t.Fatal(err)
+		}
+	})
+	assert.Zero(t, allocs)
+	assert.Equal(t, want.Types, a.Types)
+	assert.Equal(t, want.Sizes, a.Sizes)
+	assert.Equal(t, want.Hashes, a.Hashes)
+
+	// the announcement does not point to the decoded bytes
+	decoded := bytes.Clone(b)
+	a.Reset()
+	assert.NoError(t, a.Add(&genTx.CustomTx{TxType: types.DynamicFeeTxType, SignedRlpBytes: []byte{0x81, 0x02}}))
+	assert.Equal(t, decoded, b)
+}
+
+func FuzzDecodeAnnouncement(f *testing.F) {
+	txs := txsForTests(f)
+	for i := range txs {
+		packet := announcementForTests(txs[:i+1])
+		b, err := rlp.EncodeToBytes(&packet)
+		if err != nil {
+			f.Fatalf("Failed to RLP encode announcement: %v", err)
+		}
+		f.Add(b)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		a, err := DecodeAnnouncement(reader.NewStrictReader(data))
+		if err != nil {
+			return
+		}
+		// what is decoded in strict mode is decoded the same by geth
+		var want eth.NewPooledTransactionHashesPacket
+		r := reader.NewReader(data)
+		_, _ = DecodeAnnouncement(r)
+		if err = rlp.DecodeBytes(data[:r.Pos()], &want); err != nil {
+			t.Fatalf("decoded by prlp but not by geth: %v", err)
+		}
+		assert.Equal(t, len(want.Hashes), a.Len())
+		for i := range want.Hashes {
+			assert.Equal(t, want.Types[i], a.Types[i])
+			assert.Equal(t, want.Sizes[i], a.Sizes[i])
+			assert.Equal(t, want.Hashes[i], a.Hashes[i])
+		}
+		assert.Equal(t, data[:r.Pos()], a.AppendRLP(nil))
+	})
+}
+
+func TestDecodeAnnouncement_Errors(t *testing.T) {
+	hash := append([]byte{0xa0}, make([]byte, 32)...)
+	list := func(items ...[]byte) []byte {
+		b, err := rlp.EncodeToBytes(items)
+		assert.NoError(t, err)
+		return b
+	}
+	raw := func(items ...[]byte) []byte {
+		values := make([]rlp.RawValue, len(items))
+		for i, item := range items {
+			values[i] = item
+		}
+		b, err := rlp.EncodeToBytes(values)
+		assert.NoError(t, err)
+		return b
+	}
+	tests := []struct {
+		name  string
+		data  []byte
+		err   error
+		field string
+	}{
+		{"not a list", []byte{0x80}, errors.ErrNotAList, ""},
+		{"types is a list", raw(list(), list(), list()), errors.ErrNotAString, "types"},
+		{"size too large", raw([]byte{0x02}, raw([]byte{0x85, 0x01, 0, 0, 0, 0}), raw(hash)), errors.ErrUnexpectedLength, "sizes[0]"},
+		{"short hash", raw([]byte{0x02}, raw([]byte{0x01}), raw(hash[1:])), errors.ErrUnexpectedLength, "hashes[0]"},
+		// the last item of a list must not end past the list
+		{"size overruns sizes list", raw([]byte{0x02}, []byte{0xc1, 0x81, 0x80}, raw(hash)), errors.ErrUnexpectedLength, "sizes"},
+		{"hash overruns hashes list", raw([]byte{0x02}, raw([]byte{0x01}), append([]byte{0xc1}, hash...)), errors.ErrUnexpectedLength, "hashes"},
+		{"missing hashes", raw([]byte{0x02}, raw([]byte{0x01})), errors.ErrUnexpectedEOF, "hashes"},
+		{"more sizes", raw([]byte{0x02}, raw([]byte{0x01}, []byte{0x01}), raw(hash)), errors.ErrUnexpectedLength, ""},
+		{"trailing field", raw([]byte{0x02}, raw([]byte{0x01}), raw(hash), []byte{0x01}), errors.ErrUnexpectedLength, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeAnnouncement(reader.NewReader(tt.data))
+			assert.ErrorIs(t, err, tt.err)
+			if tt.field != "" {
+				assert.Equal(t, tt.field, errors.GetPosition(err).Field)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package pooledTx
+
+import (
+	"bytes"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/genTx"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DecodeGetPooledTxsPacket decodes a GetPooledTransactions request, the request id followed by the list of the hashes
+// of the requested txs.
+func DecodeGetPooledTxsPacket(r *reader.RlpReader) (uint64, []common.Hash, error) {
+	return DecodeGetPooledTxsPacketInto(r, nil)
+}
+
+// DecodeGetPooledTxsPacketInto is the same as DecodeGetPooledTxsPacket but decoding the hashes into the array of dst,
+// so draining requests into the same slice does not allocate once it is large enough.
+func DecodeGetPooledTxsPacketInto(r *reader.RlpReader, dst []common.Hash) (uint64, []common.Hash, error) {
+	start := r.Pos()
+	listSize, err := r.ReadListSize()
+	if err != nil {
+		return 0, dst[:0], err
+	}
+	end := r.Pos() + listSize
+	requestID, err := r.DecodeUint64()
+	if err != nil {
+		return 0, dst[:0], errors.WithField(err, "requestId")
+	}
+	hashes, err := decodeHashesInto(r, dst)
+	if err != nil {
+		return requestID, hashes, errors.WithField(err, "hashes")
+	}
+	if r.Pos() != end {
+		err = errors.ErrUnexpectedLength.WithMessagef("request ends at %d instead of %d", r.Pos(), end)
+		return requestID, hashes, errors.AtOffset(err, start)
+	}
+	return requestID, hashes, nil
+}
+
+// EncodeGetPooledTxsPacket writes a GetPooledTransactions request for the txs of the hashes
+func EncodeGetPooledTxsPacket(buffer *bytes.Buffer, requestID uint64, hashes []common.Hash) error {
+	buffer.Grow(GetPooledTxsPacketLength(requestID, hashes))
+	_, err := buffer.Write(AppendGetPooledTxsPacket(buffer.AvailableBuffer(), requestID, hashes))
+	return err
+}
+
+// AppendGetPooledTxsPacket appends a GetPooledTransactions request for the txs of the hashes to dst and returns the
+// extended slice. dst is only reallocated when its capacity is not enough.
+func AppendGetPooledTxsPacket(dst []byte, requestID uint64, hashes []common.Hash) []byte {
+	hashesLength := len(hashes) * genTx.HashRLPLength
+	dst = genTx.AppendListLength(dst, genTx.CalculateRLP64ValueLength(requestID)+genTx.CalculateRLPListLength(hashesLength))
+	dst = genTx.AppendRLPUint64(dst, requestID)
+	dst = genTx.AppendListLength(dst, hashesLength)
+	for i := range hashes {
+		dst = genTx.AppendRLPBytes(dst, hashes[i][:])
+	}
+	return dst
+}
+
+// GetPooledTxsPacketLength returns the length of the GetPooledTransactions request for the txs of the hashes
+func GetPooledTxsPacketLength(requestID uint64, hashes []common.Hash) int {
+	return genTx.CalculateRLPListLength(genTx.CalculateRLP64ValueLength(requestID) +
+		genTx.CalculateRLPListLength(len(hashes)*genTx.HashRLPLength))
+}
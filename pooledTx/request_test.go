@@ -0,0 +1,142 @@
+package pooledTx
+
+import (
+	"bytes"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+// hashesForTests returns n different hashes
+func hashesForTests(n int) []common.Hash {
+	hashes := make([]common.Hash, n)
+	for i := range hashes {
+		hashes[i] = crypto.Keccak256Hash(big.NewInt(int64(i)).Bytes())
+	}
+	return hashes
+}
+
+func TestGetPooledTxsPacket(t *testing.T) {
+	for _, requestID := range []uint64{0, 1, 0x80, 1<<64 - 1} {
+		for _, n := range []int{0, 1, 2, 1024} {
+			want := eth.GetPooledTransactionsPacket{
+				RequestId:                    requestID,
+				GetPooledTransactionsRequest: hashesForTests(n),
+			}
+			wantRLP, err := rlp.EncodeToBytes(&want)
+			assert.NoError(t, err)
+
+			buffer := new(bytes.Buffer)
+			assert.NoError(t, EncodeGetPooledTxsPacket(buffer, requestID, want.GetPooledTransactionsRequest))
+			assert.Equal(t, wantRLP, buffer.Bytes(), "request id %d, %d hashes", requestID, n)
+			assert.Equal(t, len(wantRLP), GetPooledTxsPacketLength(requestID, want.GetPooledTransactionsRequest))
+			got := AppendGetPooledTxsPacket([]byte{0x01}, requestID, want.GetPooledTransactionsRequest)
+			assert.Equal(t, append([]byte{0x01}, wantRLP...), got)
+
+			r := reader.NewStrictReader(wantRLP)
+			gotID, hashes, err := DecodeGetPooledTxsPacket(r)
+			assert.NoError(t, err)
+			assert.Zero(t, r.Len())
+			assert.Equal(t, requestID, gotID)
+			assert.Equal(t, len(want.GetPooledTransactionsRequest), len(hashes))
+			for i := range hashes {
+				assert.Equal(t, want.GetPooledTransactionsRequest[i], hashes[i])
+			}
+
+			// decoded back by geth
+			var decoded eth.GetPooledTransactionsPacket
+			assert.NoError(t, rlp.DecodeBytes(buffer.Bytes(), &decoded))
+			assert.Equal(t, requestID, decoded.RequestId)
+			assert.Equal(t, len(want.GetPooledTransactionsRequest), len(decoded.GetPooledTransactionsRequest))
+		}
+	}
+}
+
+func TestDecodeGetPooledTxsPacketInto(t *testing.T) {
+	want := hashesForTests(64)
+	b := AppendGetPooledTxsPacket(nil, 7, want)
+
+	dst := make([]common.Hash, 0, len(want))
+	r := reader.NewReader(b)
+	allocs := testing.AllocsPerRun(10, func() {
+		var err error
+		r.Reset(b)
+		if _, dst, err = DecodeGetPooledTxsPacketInto(r, dst); err != nil {
+			t.Fatal(err)
+		}
+	})
+	assert.Zero(t, allocs)
+	assert.Equal(t, want, dst)
+
+	// the hashes of an announcement are requested as they were decoded
+	a := &Announcement{Types: make([]byte, len(want)), Sizes: make([]uint32, len(want)), Hashes: want}
+	decoded, err := DecodeAnnouncement(reader.NewReader(a.AppendRLP(nil)))
+	assert.NoError(t, err)
+	assert.Equal(t, b, AppendGetPooledTxsPacket(nil, 7, decoded.Hashes))
+}
+
+func TestDecodeGetPooledTxsPacket_Errors(t *testing.T) {
+	hash := append([]byte{0xa0}, make([]byte, 32)...)
+	raw := func(items ...[]byte) []byte {
+		values := make([]rlp.RawValue, len(items))
+		for i, item := range items {
+			values[i] = item
+		}
+		b, err := rlp.EncodeToBytes(values)
+		assert.NoError(t, err)
+		return b
+	}
+	tests := []struct {
+		name  string
+		data  []byte
+		err   error
+		field string
+	}{
+		{"not a list", []byte{0x80}, errors.ErrNotAList, ""},
+		// lists are only rejected as integers in strict mode
+		{"request id is a list", raw(raw(), raw(hash)), errors.ErrNotAString, "requestId"},
+		{"hashes not a list", raw([]byte{0x01}, hash), errors.ErrNotAList, "hashes"},
+		{"short hash", raw([]byte{0x01}, raw(hash[1:])), errors.ErrUnexpectedLength, "hashes[0]"},
+		{"hash is a list", raw([]byte{0x01}, raw(raw(hash))), errors.ErrNotAString, "hashes[0]"},
+		{"trailing field", raw([]byte{0x01}, raw(hash), []byte{0x01}), errors.ErrUnexpectedLength, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := DecodeGetPooledTxsPacket(reader.NewStrictReader(tt.data))
+			assert.ErrorIs(t, err, tt.err)
+			if tt.field != "" {
+				assert.Equal(t, tt.field, errors.GetPosition(err).Field)
+			}
+		})
+	}
+}
+
+func FuzzDecodeGetPooledTxsPacket(f *testing.F) {
+	for _, n := range []int{0, 1, 3} {
+		f.Add(AppendGetPooledTxsPacket(nil, uint64(n), hashesForTests(n)))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := reader.NewStrictReader(data)
+		requestID, hashes, err := DecodeGetPooledTxsPacket(r)
+		if err != nil {
+			return
+		}
+		// what is decoded in strict mode is decoded the same by geth
+		var want eth.GetPooledTransactionsPacket
+		if err = rlp.DecodeBytes(data[:r.Pos()], &want); err != nil {
+			t.Fatalf("decoded by prlp but not by geth: %v", err)
+		}
+		assert.Equal(t, want.RequestId, requestID)
+		assert.Equal(t, len(want.GetPooledTransactionsRequest), len(hashes))
+		for i := range hashes {
+			assert.Equal(t, want.GetPooledTransactionsRequest[i], hashes[i])
+		}
+		assert.Equal(t, data[:r.Pos()], AppendGetPooledTxsPacket(nil, requestID, hashes))
+	})
+}
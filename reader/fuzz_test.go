@@ -0,0 +1,67 @@
+package reader
+
+import (
+	"bytes"
+	"github.com/ethereum/go-ethereum/rlp"
+	"testing"
+)
+
+// compareWithGeth walks the values of data with go-ethereum rlp.Split and with a strict RlpReader, which must accept
+// and reject the same encodings and decode the same values
+func compareWithGeth(t *testing.T, data []byte, depth int) {
+	r := NewStrictReader(data)
+	rest := data
+	for len(rest) > 0 {
+		kind, content, tail, gethErr := rlp.Split(rest)
+		isList := r.IsNextValAList()
+		pos := r.Pos()
+		value, err := r.DecodeNextValue()
+		if gethErr != nil {
+			if err == nil {
+				t.Fatalf("value at %d accepted, go-ethereum rejects it: %v", pos, gethErr)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("value at %d rejected, go-ethereum accepts it: %v", pos, err)
+		}
+		if isList != (kind == rlp.List) {
+			t.Fatalf("value at %d is a list: %v, go-ethereum kind: %v", pos, isList, kind)
+		}
+		if !bytes.Equal(value, content) {
+			t.Fatalf("value at %d is %x, go-ethereum decodes %x", pos, value, content)
+		}
+		if kind == rlp.List && depth < 64 {
+			compareWithGeth(t, content, depth+1)
+		}
+		rest = tail
+	}
+	if r.Len() != 0 {
+		t.Fatalf("%d bytes left", r.Len())
+	}
+}
+
+func FuzzRlpReader(f *testing.F) {
+	f.Add([]byte{0x80})
+	f.Add([]byte{0xc3, 0x01, 0x82, 0x01, 0x02})
+	f.Add([]byte{0xc7, 0xc0, 0xc1, 0xc0, 0xc3, 0xc0, 0xc1, 0xc0})
+	f.Add(append([]byte{0xb8, 0x38}, make([]byte, 0x38)...))
+	f.Add(append([]byte{0xf8, 0x3a, 0xb8, 0x38}, make([]byte, 0x38)...))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		compareWithGeth(t, data, 0)
+
+		// the lenient reader must not panic either
+		r := NewReader(data)
+		for r.Len() > 0 {
+			if r.IsNextValAList() {
+				if _, err := r.ReadListSize(); err != nil {
+					return
+				}
+				continue
+			}
+			if _, err := r.DecodeNextUint(); err != nil {
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,213 @@
+package reader
+
+import (
+	"fmt"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+	"math/big"
+	"strings"
+)
+
+// SkipValue skips the next value, string or list, without decoding it
+func (r *RlpReader) SkipValue() error {
+	var size uint64
+	var err error
+	if r.IsNextValAList() {
+		size, err = r.ReadListSize()
+	} else {
+		size, err = r.ReadValueSize()
+	}
+	if err != nil {
+		return err
+	}
+	return r.Skip(size)
+}
+
+// SeekPath moves the reader to the start of the value found following the path of list indexes from the current
+// value, e.g. [1, 300, 5] is the item 5 of the item 300 of the item 1. Only the values before the ones in the path
+// are skipped, nothing else is decoded.
+//
+// Typed tx envelopes (a type byte followed by a list) are entered as their list, whether they are the current value
+// or a string holding one, so the type byte does not take an index. Paths are still per layout: every tx type has
+// its own field order, e.g. the nonce is [0] in a legacy tx but [1] in a dynamic fee tx, which starts with the chain
+// id. Blob txs in their network form wrap the tx fields in another list, followed by the sidecar, so their fields are
+// one level deeper: the nonce of the blob tx i of a packet is [i, 0, 1] instead of [i, 1].
+func (r *RlpReader) SeekPath(path ...int) error {
+	for depth, index := range path {
+		if err := r.seekIndex(index, depth == 0); err != nil {
+			return errors.WithField(err, pathString(path[:depth+1]))
+		}
+	}
+	return nil
+}
+
+// seekIndex enters the next value, which must be a list or a typed envelope, and moves to its item index
+func (r *RlpReader) seekIndex(index int, topLevel bool) error {
+	start := r.currentPos
+	if index < 0 {
+		return errors.AtOffset(errors.ErrValueNotSupport.WithMessagef("negative index %d", index), start)
+	}
+	// the list must end before limit, the end of the string holding the envelope or of the bytes
+	limit := r.length
+	if !r.IsNextValAList() {
+		if !topLevel || !isEnvelopeStart(r.bytes[start:]) {
+			size, err := r.ReadValueSize()
+			if err != nil {
+				return err
+			}
+			if !r.EnoughBytes(size) || !isEnvelopeStart(r.bytes[r.currentPos:r.currentPos+size]) {
+				return errors.AtOffset(errors.ErrNotAList, start)
+			}
+			limit = r.currentPos + size
+		}
+		// skip the tx type
+		r.increasePos(1)
+	}
+	size, err := r.ReadListSize()
+	if err != nil {
+		return err
+	}
+	if r.currentPos+size > limit {
+		return errors.AtOffset(errors.ErrUnexpectedEOF, start)
+	}
+	end := r.currentPos + size
+	for i := 0; i < index && r.currentPos < end; i++ {
+		if err = r.SkipValue(); err != nil {
+			return err
+		}
+	}
+	if r.currentPos > end {
+		return errors.AtOffset(errors.ErrUnexpectedLength.WithMessage("list items exceed the list size"), start)
+	}
+	if r.currentPos == end {
+		return errors.AtOffset(errors.ErrUnexpectedLength.WithMessagef("index %d out of range", index), start)
+	}
+	return nil
+}
+
+// isEnvelopeStart checks whether b starts with a tx type followed by a list
+func isEnvelopeStart(b []byte) bool {
+	return len(b) >= 2 && b[0] < 0x80 && b[1] >= 0xc0
+}
+
+func pathString(path []int) string {
+	var sb strings.Builder
+	for _, index := range path {
+		_, _ = fmt.Fprintf(&sb, "[%d]", index)
+	}
+	return sb.String()
+}
+
+// seekPath creates a reader over b positioned at the value of the path
+func seekPath(b []byte, path []int) (*RlpReader, error) {
+	r := NewReader(b)
+	if err := r.SeekPath(path...); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// PathRaw returns the encoding, header included, of the value of the path in b. See SeekPath
+func PathRaw(b []byte, path ...int) ([]byte, error) {
+	r, err := seekPath(b, path)
+	if err != nil {
+		return nil, err
+	}
+	start := r.currentPos
+	if err = r.SkipValue(); err != nil {
+		return nil, errors.WithField(err, pathString(path))
+	}
+	return r.GetBytes(start, r.currentPos), nil
+}
+
+// PathValue returns the content of the string of the path in b. See SeekPath
+func PathValue(b []byte, path ...int) ([]byte, error) {
+	r, err := seekPath(b, path)
+	if err != nil {
+		return nil, err
+	}
+	v, err := decodePathString(r)
+	if err != nil {
+		return nil, errors.WithField(err, pathString(path))
+	}
+	return v, nil
+}
+
+func decodePathString(r *RlpReader) ([]byte, error) {
+	if r.IsNextValAList() {
+		return nil, errors.AtOffset(errors.ErrNotAString, r.currentPos)
+	}
+	return r.DecodeNextValue()
+}
+
+// pathUint returns the bytes of the integer of the path in b, rejecting integers of more than maxBytes
+func pathUint(b []byte, path []int, maxBytes int) ([]byte, error) {
+	r, err := seekPath(b, path)
+	if err != nil {
+		return nil, err
+	}
+	start := r.currentPos
+	if r.IsNextValAList() {
+		return nil, errors.WithField(errors.AtOffset(errors.ErrNotAString, start), pathString(path))
+	}
+	v, err := r.DecodeNextUint()
+	if err != nil {
+		return nil, errors.WithField(err, pathString(path))
+	}
+	if len(v) > maxBytes {
+		err = errors.ErrValueNotSupport.WithMessagef("integer of %d bytes, max %d", len(v), maxBytes)
+		return nil, errors.WithField(errors.AtOffset(err, start), pathString(path))
+	}
+	return v, nil
+}
+
+// PathUint64 decodes the integer of the path in b. See SeekPath
+func PathUint64(b []byte, path ...int) (uint64, error) {
+	v, err := pathUint(b, path, 8)
+	if err != nil {
+		return 0, err
+	}
+	return BytesToUint64(v), nil
+}
+
+// PathBigInt decodes the integer of the path in b. See SeekPath
+func PathBigInt(b []byte, path ...int) (*big.Int, error) {
+	v, err := pathUint(b, path, 32)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(v), nil
+}
+
+// PathUint256 decodes the integer of the path in b. See SeekPath
+func PathUint256(b []byte, path ...int) (*uint256.Int, error) {
+	v, err := pathUint(b, path, 32)
+	if err != nil {
+		return nil, err
+	}
+	return new(uint256.Int).SetBytes(v), nil
+}
+
+// PathAddress decodes the address of the path in b. An empty string, the to of a contract creation, returns nil.
+// See SeekPath
+func PathAddress(b []byte, path ...int) (*common.Address, error) {
+	r, err := seekPath(b, path)
+	if err != nil {
+		return nil, err
+	}
+	start := r.currentPos
+	v, err := decodePathString(r)
+	if err != nil {
+		return nil, errors.WithField(err, pathString(path))
+	}
+	if len(v) == 0 {
+		return nil, nil
+	}
+	if len(v) != common.AddressLength {
+		err = errors.ErrUnexpectedLength.WithMessagef("address of %d bytes", len(v))
+		return nil, errors.WithField(errors.AtOffset(err, start), pathString(path))
+	}
+	address := common.BytesToAddress(v)
+	return &address, nil
+}
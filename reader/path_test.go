@@ -0,0 +1,101 @@
+package reader
+
+import (
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestPathAccess(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	to := common.HexToAddress("0x2f2a2543b76a4166549f7aab2e75bef0aefc5b0f")
+	gasFeeCap, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	txsData := []types.TxData{
+		&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1e9), Gas: 21000, To: &to, Value: big.NewInt(1)},
+		&types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 300, GasTipCap: big.NewInt(1), GasFeeCap: gasFeeCap,
+			Gas: 21000},
+		&types.BlobTx{ChainID: uint256.NewInt(1), Nonce: 4, GasTipCap: uint256.NewInt(1),
+			GasFeeCap: uint256.MustFromBig(gasFeeCap), Gas: 21000, To: to, BlobFeeCap: uint256.NewInt(1),
+			BlobHashes: []common.Hash{{0x01}}},
+	}
+	txs := make([]*types.Transaction, 0, len(txsData))
+	for _, txData := range txsData {
+		tx, err := types.SignNewTx(privKey, types.LatestSignerForChainID(big.NewInt(1)), txData)
+		assert.NoError(t, err)
+		txs = append(txs, tx)
+	}
+	// eth/66 PooledTransactions: [requestId, [txs...]]
+	packet, err := rlp.EncodeToBytes([]interface{}{uint64(7), txs})
+	assert.NoError(t, err)
+
+	requestId, err := PathUint64(packet, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), requestId)
+
+	// legacy tx: [nonce, gasPrice, gas, to, ...]
+	address, err := PathAddress(packet, 1, 0, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, &to, address)
+	// typed txs: [chainId, nonce, gasTipCap, gasFeeCap, gas, to, ...]
+	nonce, err := PathUint64(packet, 1, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(300), nonce)
+	address, err = PathAddress(packet, 1, 1, 5)
+	assert.NoError(t, err)
+	assert.Nil(t, address)
+	feeCap, err := PathBigInt(packet, 1, 1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, gasFeeCap, feeCap)
+	feeCap256, err := PathUint256(packet, 1, 2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint256.MustFromBig(gasFeeCap), feeCap256)
+	blobHash, err := PathValue(packet, 1, 2, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, common.Hash{0x01}.Bytes(), blobHash)
+
+	// the raw tx is its encoding in the packet, and it can be navigated on its own
+	raw, err := PathRaw(packet, 1, 2)
+	assert.NoError(t, err)
+	binary, err := txs[2].MarshalBinary()
+	assert.NoError(t, err)
+	_, content, _, err := rlp.Split(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, binary, content)
+	feeCap256, err = PathUint256(binary, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint256.MustFromBig(gasFeeCap), feeCap256)
+
+	// in the network form the blob tx fields are one level deeper, followed by the sidecar
+	network, err := rlp.EncodeToBytes([]*types.Transaction{txs[2].WithBlobTxSidecar(&types.BlobTxSidecar{})})
+	assert.NoError(t, err)
+	nonce, err = PathUint64(network, 0, 0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), nonce)
+	_, err = PathUint64(network, 0, 1)
+	assert.ErrorIs(t, err, errors.ErrNotAString)
+
+	// errors are located
+	_, err = PathUint64(packet, 1, 3)
+	var pErr *errors.PErrors
+	assert.ErrorAs(t, err, &pErr)
+	assert.Equal(t, "index 3 out of range", pErr.Details)
+	assert.Equal(t, "[1][3]", errors.GetPosition(err).Field)
+	_, err = PathUint64(packet, 1, 1, 3)
+	assert.ErrorAs(t, err, &pErr)
+	assert.Equal(t, "integer of 13 bytes, max 8", pErr.Details)
+	_, err = PathUint64(packet, 0, 0)
+	assert.ErrorIs(t, err, errors.ErrNotAList)
+	_, err = PathValue(packet, 1, 1, 8)
+	assert.ErrorIs(t, err, errors.ErrNotAString)
+	_, err = PathAddress(packet, 1, 1, 1)
+	assert.ErrorIs(t, err, errors.ErrUnexpectedLength)
+	_, err = PathUint64(packet[:len(packet)-1], 1, 2, 1)
+	assert.Error(t, err)
+}
@@ -6,10 +6,16 @@ import (
 	"io"
 )
 
+// RlpReader decodes RLP values from a byte slice. Its errors are errors.PErrors carrying the offset where the value
+// failing to decode starts.
 type RlpReader struct {
 	bytes      []byte
 	currentPos uint64
 	length     uint64
+	// strict rejects non-canonical encodings, so the same value can only be encoded in one way
+	strict bool
+	// copyValues makes the values returned a copy of the bytes instead of pointing to them
+	copyValues bool
 }
 
 func (r *RlpReader) Len() uint64 {
@@ -28,7 +34,100 @@ func NewReader(bytes []byte) *RlpReader {
 	}
 }
 
+// Reset makes the reader read the bytes from their start, keeping its strict and copy modes
+func (r *RlpReader) Reset(bytes []byte) {
+	r.bytes = bytes
+	r.currentPos = 0
+	r.length = uint64(len(bytes))
+}
+
+// NewStrictReader creates a reader that rejects non-canonical encodings. See SetStrict
+func NewStrictReader(bytes []byte) *RlpReader {
+	r := NewReader(bytes)
+	r.strict = true
+	return r
+}
+
+// SetStrict enables or disables the strict mode. In strict mode the reader rejects:
+//   - sizes with leading zero bytes (errors.ErrSizeLeadingZero)
+//   - sizes < 56 encoded in the long form (errors.ErrNonCanonicalSize)
+//   - single bytes < 0x80 encoded as a one byte string (errors.ErrCannotValueBeASingleByte)
+//   - integers with leading zero bytes when decoded with DecodeNextUint or DecodeUint64 (errors.ErrIntLeadingZero)
+func (r *RlpReader) SetStrict(strict bool) {
+	r.strict = strict
+}
+
+// Strict returns whether the reader rejects non-canonical encodings
+func (r *RlpReader) Strict() bool {
+	return r.strict
+}
+
+// NewCopyReader creates a reader whose decoded values do not point to the bytes. See SetCopyValues
+func NewCopyReader(bytes []byte) *RlpReader {
+	r := NewReader(bytes)
+	r.copyValues = true
+	return r
+}
+
+// SetCopyValues enables or disables copying the values. When enabled the slices returned by GetBytes,
+// DecodeNextValue and DecodeNextUint are copies, so whatever is decoded from them, like the txs of a packet, stays
+// valid after the bytes are modified or reused. Read still returns the bytes themselves.
+func (r *RlpReader) SetCopyValues(copyValues bool) {
+	r.copyValues = copyValues
+}
+
+// CopyValues returns whether the values returned by the reader are copies of its bytes
+func (r *RlpReader) CopyValues() bool {
+	return r.copyValues
+}
+
+// value returns v, or a copy of it when the reader copies its values
+func (r *RlpReader) value(v []byte) []byte {
+	if !r.copyValues || len(v) == 0 {
+		return v
+	}
+	c := make([]byte, len(v))
+	copy(c, v)
+	return c
+}
+
+// readLongSize reads the size of a value encoded in the long form, which uses dlSize bytes
+func (r *RlpReader) readLongSize(dlSize uint64) (uint64, error) {
+	d, err := r.Read(dlSize)
+	if err != nil {
+		return 0, err
+	}
+	size := BytesToUint64(d)
+	if r.strict {
+		if err = CheckCanonicalSize(d, size); err != nil {
+			return 0, err
+		}
+	}
+	return size, nil
+}
+
+// CheckCanonicalSize checks that a size encoded in the long form with the sizeBytes has no leading zeros and that it
+// could not have been encoded in the short form
+func CheckCanonicalSize(sizeBytes []byte, size uint64) error {
+	if len(sizeBytes) > 0 && sizeBytes[0] == 0 {
+		return errors.ErrSizeLeadingZero
+	}
+	if size < 56 {
+		return errors.ErrNonCanonicalSize
+	}
+	return nil
+}
+
 func (r *RlpReader) ReadValueSize() (uint64, error) {
+	start := r.currentPos
+	v, err := r.readValueSize()
+	if err != nil {
+		return 0, errors.AtOffset(err, start)
+	}
+	return v, nil
+}
+
+func (r *RlpReader) readValueSize() (uint64, error) {
 	for r.Len() > 0 {
 		c, err := r.ReadByte()
 		switch {
@@ -46,7 +145,7 @@ func (r *RlpReader) ReadValueSize() (uint64, error) {
 				// get dl size c- 0xb8
 				dlSize := c - 0xb7
 				// read dl
-				dl, err := r.ReadSize(uint64(dlSize))
+				dl, err := r.readLongSize(uint64(dlSize))
 				if err != nil {
 					return 0, err
 				}
@@ -56,7 +155,7 @@ func (r *RlpReader) ReadValueSize() (uint64, error) {
 			{
 				// string size 0-55 bytes
 				// get dl size c - 0x80
-				return uint64(c - 0x8), nil
+				return uint64(c - 0x80), nil
 			}
 		default:
 			{
@@ -70,6 +169,15 @@ func (r *RlpReader) ReadValueSize() (uint64, error) {
 }
 
 func (r *RlpReader) ReadListSize() (uint64, error) {
+	start := r.currentPos
+	v, err := r.readListSize()
+	if err != nil {
+		return 0, errors.AtOffset(err, start)
+	}
+	return v, nil
+}
+
+func (r *RlpReader) readListSize() (uint64, error) {
 	c, err := r.ReadByte()
 	if err != nil {
 		return 0, err
@@ -82,7 +190,7 @@ func (r *RlpReader) ReadListSize() (uint64, error) {
 			// get dl size c - 0xf8
 			dlSize := c - 0xf7
 			// read dl
-			size, err = r.ReadSize(uint64(dlSize))
+			size, err = r.readLongSize(uint64(dlSize))
 			if err != nil {
 				return 0, err
 			}
@@ -104,7 +212,7 @@ func (r *RlpReader) ReadByte() (byte, error) {
 		defer r.increasePos(1)
 		return r.bytes[r.currentPos], nil
 	} else {
-		return 0x0, io.EOF
+		return 0x0, errors.AtOffset(io.EOF, r.currentPos)
 	}
 }
 
@@ -121,7 +229,7 @@ func (r *RlpReader) Read(length uint64) ([]byte, error) {
 		defer r.increasePos(length)
 		return r.bytes[r.currentPos : r.currentPos+length], nil
 	} else {
-		return nil, io.EOF
+		return nil, errors.AtOffset(io.EOF, r.currentPos)
 	}
 }
 
@@ -129,7 +237,7 @@ func (r *RlpReader) Skip(length uint64) error {
 	if r.Len() >= length {
 		r.increasePos(length)
 	} else {
-		return io.EOF
+		return errors.AtOffset(io.EOF, r.currentPos)
 	}
 	return nil
 }
@@ -138,15 +246,62 @@ func (r *RlpReader) increasePos(i uint64) {
 	r.currentPos += i
 }
 
+// DecodeUint64 decodes the next value as an unsigned integer, which must fit in 64 bits. See DecodeNextUint
 func (r *RlpReader) DecodeUint64() (uint64, error) {
-	v, err := r.DecodeNextValue()
+	start := r.currentPos
+	v, err := r.decodeNextUint()
+	if err == nil {
+		err = checkUint64Length(v)
+	}
 	if err != nil {
-		return 0, err
+		return 0, errors.AtOffset(err, start)
 	}
 	return BytesToUint64(v), nil
 }
 
+// checkUint64Length checks that the big endian bytes of an integer fit in 64 bits
+func checkUint64Length(v []byte) error {
+	if len(v) > 8 {
+		return errors.ErrUnexpectedLength.WithMessagef("integer of %d bytes does not fit in 64 bits", len(v))
+	}
+	return nil
+}
+
+// DecodeNextUint decodes the next value as an unsigned integer, returning its big endian bytes.
+// In strict mode lists and integers with leading zero bytes are rejected.
+func (r *RlpReader) DecodeNextUint() ([]byte, error) {
+	start := r.currentPos
+	v, err := r.decodeNextUint()
+	if err != nil {
+		return []byte{}, errors.AtOffset(err, start)
+	}
+	return r.value(v), nil
+}
+
+func (r *RlpReader) decodeNextUint() ([]byte, error) {
+	if r.strict && r.Len() > 0 && r.bytes[r.currentPos] >= 0xc0 {
+		return []byte{}, errors.ErrNotAString
+	}
+	v, err := r.decodeNextValue()
+	if err != nil {
+		return []byte{}, err
+	}
+	if r.strict && len(v) > 0 && v[0] == 0 {
+		return []byte{}, errors.ErrIntLeadingZero
+	}
+	return v, nil
+}
+
 func (r *RlpReader) DecodeNextValue() ([]byte, error) {
+	start := r.currentPos
+	v, err := r.decodeNextValue()
+	if err != nil {
+		return []byte{}, errors.AtOffset(err, start)
+	}
+	return r.value(v), nil
+}
+
+func (r *RlpReader) decodeNextValue() ([]byte, error) {
 	for r.Len() > 0 {
 		c, err := r.ReadByte()
 		switch {
@@ -160,7 +315,7 @@ func (r *RlpReader) DecodeNextValue() ([]byte, error) {
 				// get dl size c - 0xf8
 				dlSize := c - 0xf7
 				// read dl
-				dl, err := r.ReadSize(uint64(dlSize))
+				dl, err := r.readLongSize(uint64(dlSize))
 				if err != nil {
 					return []byte{}, err
 				}
@@ -181,7 +336,7 @@ func (r *RlpReader) DecodeNextValue() ([]byte, error) {
 				// get dl size c- 0xb8
 				dlSize := c - 0xb7
 				// read dl
-				dl, err := r.ReadSize(uint64(dlSize))
+				dl, err := r.readLongSize(uint64(dlSize))
 				if err != nil {
 					return []byte{}, err
 				}
@@ -194,11 +349,16 @@ func (r *RlpReader) DecodeNextValue() ([]byte, error) {
 				// get dl size c - 0x80
 				dl := c - 0x80
 				// read bytes(dl)
-				return r.Read(uint64(dl))
+				v, err := r.Read(uint64(dl))
+				if err == nil && r.strict && dl == 1 && v[0] < 0x80 {
+					return []byte{}, errors.ErrCannotValueBeASingleByte
+				}
+				return v, err
 			}
 		default:
 			{
-				return []byte{c}, nil
+				// the byte is its own encoding, return it from the bytes like any other value
+				return r.bytes[r.currentPos-1 : r.currentPos], nil
 			}
 		}
 	}
@@ -221,8 +381,9 @@ func BytesToUint64(b []byte) uint64 {
 	return binary.BigEndian.Uint64(buf[:])
 }
 
+// IsNextValAList checks whether the next value is a list without consuming it. Returns false if there are no bytes left
 func (r *RlpReader) IsNextValAList() bool {
-	return r.bytes[r.currentPos] >= 0xc0
+	return r.Len() > 0 && r.bytes[r.currentPos] >= 0xc0
 }
 
 func (r *RlpReader) Pos() uint64 {
@@ -230,5 +391,5 @@ func (r *RlpReader) Pos() uint64 {
 }
 
 func (r *RlpReader) GetBytes(start, end uint64) []byte {
-	return r.bytes[start:end]
+	return r.value(r.bytes[start:end])
 }
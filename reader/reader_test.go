@@ -0,0 +1,33 @@
+package reader
+
+import (
+	"bytes"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReadValueSize(t *testing.T) {
+	// sizes around the boundary of the short (0x80-0xb7) and long (0xb8-0xbf) string prefixes
+	for _, size := range []int{1, 2, 54, 55, 56, 57, 255, 256, 1024, 1 << 16} {
+		value := bytes.Repeat([]byte{0xfa}, size)
+		enc, err := rlp.EncodeToBytes(value)
+		assert.NoError(t, err)
+
+		r := NewStrictReader(enc)
+		got, err := r.ReadValueSize()
+		assert.NoError(t, err, "size %d", size)
+		assert.Equal(t, uint64(size), got, "size %d", size)
+		assert.Equal(t, uint64(size), r.Len(), "size %d", size)
+
+		r = NewStrictReader(enc)
+		v, err := r.DecodeNextValue()
+		assert.NoError(t, err, "size %d", size)
+		assert.Equal(t, value, v, "size %d", size)
+
+		s := NewStreamReader(bytes.NewReader(enc), 0)
+		got, err = s.ReadValueSize()
+		assert.NoError(t, err, "size %d", size)
+		assert.Equal(t, uint64(size), got, "size %d", size)
+	}
+}
@@ -0,0 +1,383 @@
+package reader
+
+import (
+	"bytes"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
+	"io"
+	"math"
+)
+
+// DefaultStreamWindow is the window size used when a non positive window is provided to NewStreamReader. It is kept
+// small, as bufio does, because every value returned from a window keeps the whole window alive
+const DefaultStreamWindow = 4096
+
+// maxConsecutiveEmptyReads is how many reads returning no bytes and no error are tolerated before giving up
+const maxConsecutiveEmptyReads = 100
+
+// StreamReader has the same API as RlpReader but reads the RLP values from an io.Reader, keeping at most a window
+// of bytes buffered. Values that fit in the window are returned as slices of it (zero-copy). The window is never
+// overwritten, when it needs to be refilled a new one is allocated, so the returned slices stay valid.
+// Values bigger than the window are read into their own slice.
+//
+// This trades one allocation of the window size per refill for the zero-copy: the txs decoded from a stream keep
+// pointing to their window. A window is only kept alive by the values returned from it, so the memory held by the
+// reader itself stays bounded by one window however long the stream is. Retaining a small value retains its whole
+// window, so big windows only pay off when most of what is read from them is kept.
+//
+// An io.Reader that keeps returning no bytes and no error makes the reads fail with io.ErrNoProgress.
+type StreamReader struct {
+	src    io.Reader
+	window int
+	buf    []byte
+	start  int    // first unread byte in buf
+	end    int    // end of the read bytes in buf
+	pos    uint64 // bytes consumed from src
+	strict bool   // rejects non-canonical encodings, see RlpReader.SetStrict
+}
+
+func NewStreamReader(src io.Reader, window int) *StreamReader {
+	if window <= 0 {
+		window = DefaultStreamWindow
+	}
+	return &StreamReader{
+		src:    progressReader{src},
+		window: window,
+	}
+}
+
+// progressReader fails with io.ErrNoProgress when the reader it wraps keeps returning no bytes and no error, which
+// would otherwise make the refills and the copies of big values loop forever
+type progressReader struct {
+	r io.Reader
+}
+
+func (p progressReader) Read(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	for i := 0; i < maxConsecutiveEmptyReads; i++ {
+		n, err := p.r.Read(b)
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+	return 0, io.ErrNoProgress
+}
+
+// SetStrict enables or disables the strict mode, see RlpReader.SetStrict
+func (s *StreamReader) SetStrict(strict bool) {
+	s.strict = strict
+}
+
+// Strict returns whether the reader rejects non-canonical encodings
+func (s *StreamReader) Strict() bool {
+	return s.strict
+}
+
+// readLongSize reads the size of a value encoded in the long form, which uses dlSize bytes
+func (s *StreamReader) readLongSize(dlSize uint64) (uint64, error) {
+	d, err := s.Read(dlSize)
+	if err != nil {
+		return 0, err
+	}
+	size := BytesToUint64(d)
+	if s.strict {
+		if err = CheckCanonicalSize(d, size); err != nil {
+			return 0, err
+		}
+	}
+	return size, nil
+}
+
+// Pos returns how many bytes have been consumed from the underlying reader
+func (s *StreamReader) Pos() uint64 {
+	return s.pos
+}
+
+// Buffered returns how many bytes are already read from the underlying reader but not consumed
+func (s *StreamReader) Buffered() int {
+	return s.end - s.start
+}
+
+// fill makes sure that at least n bytes are buffered
+func (s *StreamReader) fill(n int) error {
+	if s.Buffered() >= n {
+		return nil
+	}
+	if len(s.buf)-s.start < n {
+		// allocate a new window instead of moving the unread bytes to the beginning of the current one,
+		// otherwise the slices returned before would be overwritten. The old window is released once none of
+		// them points to it.
+		size := s.window
+		if n > size {
+			size = n
+		}
+		buf := make([]byte, size)
+		copy(buf, s.buf[s.start:s.end])
+		s.end -= s.start
+		s.start = 0
+		s.buf = buf
+	}
+	for s.Buffered() < n {
+		read, err := s.src.Read(s.buf[s.end:])
+		s.end += read
+		if err != nil {
+			if s.Buffered() >= n {
+				return nil
+			}
+			if err == io.ErrUnexpectedEOF {
+				return io.EOF
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *StreamReader) ReadByte() (byte, error) {
+	if err := s.fill(1); err != nil {
+		return 0x0, errors.AtOffset(err, s.pos)
+	}
+	c := s.buf[s.start]
+	s.start++
+	s.pos++
+	return c, nil
+}
+
+// Read returns the next length bytes. The returned slice points to the window when it fits on it
+func (s *StreamReader) Read(length uint64) ([]byte, error) {
+	start := s.pos
+	if length <= uint64(s.window) {
+		if err := s.fill(int(length)); err != nil {
+			return nil, errors.AtOffset(err, start)
+		}
+		d := s.buf[s.start : s.start+int(length)]
+		s.start += int(length)
+		s.pos += length
+		return d, nil
+	}
+	// the value does not fit in the window, so it is read into its own slice. It grows as the bytes arrive,
+	// so a bogus length does not allocate more than what the underlying reader has.
+	d := bytes.NewBuffer(make([]byte, 0, 2*s.window))
+	n := s.Buffered()
+	d.Write(s.buf[s.start:s.end])
+	s.start = s.end
+	s.pos += uint64(n)
+	read, err := io.CopyN(d, s.src, int64(length-uint64(n)))
+	s.pos += uint64(read)
+	if err != nil {
+		return nil, errors.AtOffset(copyError(err), start)
+	}
+	return d.Bytes(), nil
+}
+
+func (s *StreamReader) ReadSize(length uint64) (uint64, error) {
+	d, err := s.Read(length)
+	if err != nil {
+		return 0, err
+	}
+	return BytesToUint64(d), nil
+}
+
+func (s *StreamReader) Skip(length uint64) error {
+	start := s.pos
+	buffered := uint64(s.Buffered())
+	if length <= buffered {
+		s.start += int(length)
+		s.pos += length
+		return nil
+	}
+	s.start = s.end
+	s.pos += buffered
+	skipped, err := io.CopyN(io.Discard, s.src, int64(length-buffered))
+	s.pos += uint64(skipped)
+	if err != nil {
+		return errors.AtOffset(copyError(err), start)
+	}
+	return nil
+}
+
+// copyError returns the error of copying bytes from the underlying reader, io.EOF unless it made no progress
+func copyError(err error) error {
+	if err == io.ErrNoProgress {
+		return err
+	}
+	return io.EOF
+}
+
+// IsNextValAList checks whether the next value is a list without consuming it
+func (s *StreamReader) IsNextValAList() bool {
+	if err := s.fill(1); err != nil {
+		return false
+	}
+	return s.buf[s.start] >= 0xc0
+}
+
+func (s *StreamReader) ReadListSize() (uint64, error) {
+	start := s.pos
+	v, err := s.readListSize()
+	if err != nil {
+		return 0, errors.AtOffset(err, start)
+	}
+	return v, nil
+}
+
+func (s *StreamReader) readListSize() (uint64, error) {
+	c, err := s.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case c >= 0xF8:
+		// list with size > 55 bytes
+		return s.readLongSize(uint64(c - 0xf7))
+	case c >= 0xC0:
+		// list with size < 55 bytes
+		return uint64(c - 0xc0), nil
+	default:
+		return 0, errors.ErrNotAList
+	}
+}
+
+func (s *StreamReader) ReadValueSize() (uint64, error) {
+	start := s.pos
+	v, err := s.readValueSize()
+	if err != nil {
+		return 0, errors.AtOffset(err, start)
+	}
+	return v, nil
+}
+
+func (s *StreamReader) readValueSize() (uint64, error) {
+	c, err := s.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case c >= 0xC0:
+		return 0, errors.ErrNotAString
+	case c >= 0xB8:
+		// string size + 55 bytes
+		return s.readLongSize(uint64(c - 0xb7))
+	case c >= 0x80:
+		// string size 0-55 bytes
+		return uint64(c - 0x80), nil
+	default:
+		return 0, nil
+	}
+}
+
+func (s *StreamReader) DecodeNextValue() ([]byte, error) {
+	start := s.pos
+	v, err := s.decodeNextValue()
+	if err != nil {
+		return []byte{}, errors.AtOffset(err, start)
+	}
+	return v, nil
+}
+
+func (s *StreamReader) decodeNextValue() ([]byte, error) {
+	c, err := s.ReadByte()
+	if err != nil {
+		return []byte{}, err
+	}
+	var dl uint64
+	switch {
+	case c >= 0xF8:
+		// list with size > 55 bytes
+		dl, err = s.readLongSize(uint64(c - 0xf7))
+	case c >= 0xC0:
+		// list with size < 55 bytes
+		dl = uint64(c - 0xc0)
+	case c >= 0xB8:
+		// string size + 55 bytes
+		dl, err = s.readLongSize(uint64(c - 0xb7))
+	case c >= 0x80:
+		// string size 0-55 bytes
+		dl = uint64(c - 0x80)
+	default:
+		return []byte{c}, nil
+	}
+	if err != nil {
+		return []byte{}, err
+	}
+	v, err := s.Read(dl)
+	if err == nil && s.strict && c == 0x81 && v[0] < 0x80 {
+		return []byte{}, errors.ErrCannotValueBeASingleByte
+	}
+	return v, err
+}
+
+func (s *StreamReader) DecodeUint64() (uint64, error) {
+	start := s.pos
+	v, err := s.decodeUint64()
+	if err != nil {
+		return 0, errors.AtOffset(err, start)
+	}
+	return v, nil
+}
+
+func (s *StreamReader) decodeUint64() (uint64, error) {
+	if s.strict && s.IsNextValAList() {
+		return 0, errors.ErrNotAString
+	}
+	v, err := s.DecodeNextValue()
+	if err != nil {
+		return 0, err
+	}
+	if s.strict && len(v) > 0 && v[0] == 0 {
+		return 0, errors.ErrIntLeadingZero
+	}
+	if err = checkUint64Length(v); err != nil {
+		return 0, err
+	}
+	return BytesToUint64(v), nil
+}
+
+// ReadRaw returns the next value including its rlp prefix, so it can be decoded with an RlpReader.
+func (s *StreamReader) ReadRaw() ([]byte, error) {
+	start := s.pos
+	v, err := s.readRaw()
+	if err != nil {
+		return nil, errors.AtOffset(err, start)
+	}
+	return v, nil
+}
+
+func (s *StreamReader) readRaw() ([]byte, error) {
+	if err := s.fill(1); err != nil {
+		return nil, err
+	}
+	c := s.buf[s.start]
+	var headerLength, sizeLength uint64
+	switch {
+	case c >= 0xF8:
+		sizeLength = uint64(c - 0xf7)
+	case c >= 0xC0:
+	case c >= 0xB8:
+		sizeLength = uint64(c - 0xb7)
+	}
+	headerLength = 1 + sizeLength
+	if err := s.fill(int(headerLength)); err != nil {
+		return nil, err
+	}
+	var payloadLength uint64
+	switch {
+	case sizeLength > 0:
+		sizeBytes := s.buf[s.start+1 : s.start+int(headerLength)]
+		payloadLength = BytesToUint64(sizeBytes)
+		if s.strict {
+			if err := CheckCanonicalSize(sizeBytes, payloadLength); err != nil {
+				return nil, err
+			}
+		}
+	case c >= 0xC0:
+		payloadLength = uint64(c - 0xc0)
+	case c >= 0x80:
+		payloadLength = uint64(c - 0x80)
+	}
+	if payloadLength > math.MaxInt64-headerLength {
+		return nil, errors.ErrUnexpectedLength
+	}
+	return s.Read(headerLength + payloadLength)
+}
@@ -0,0 +1,115 @@
+package reader
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"runtime"
+	"testing"
+	"testing/iotest"
+)
+
+// repeatReader repeats the same bytes until n of them are read, so long streams are not held in memory
+type repeatReader struct {
+	data []byte
+	off  int
+	n    int
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	if r.n == 0 {
+		return 0, io.EOF
+	}
+	if len(p) > r.n {
+		p = p[:r.n]
+	}
+	read := 0
+	for read < len(p) {
+		c := copy(p[read:], r.data[r.off:])
+		read += c
+		r.off = (r.off + c) % len(r.data)
+	}
+	r.n -= read
+	return read, nil
+}
+
+func TestStreamReader_ReturnedValuesStayValid(t *testing.T) {
+	var stream []byte
+	for i := 0; i < 64; i++ {
+		stream = append(stream, 0x8a)
+		stream = append(stream, bytes.Repeat([]byte{byte(i)}, 10)...)
+	}
+	s := NewStreamReader(iotest.HalfReader(bytes.NewReader(stream)), 32)
+	values := make([][]byte, 0, 64)
+	for i := 0; i < 64; i++ {
+		v, err := s.DecodeNextValue()
+		assert.NoError(t, err)
+		values = append(values, v)
+	}
+	// the windows the values point to are not overwritten by the refills that came after them
+	for i, v := range values {
+		assert.Equal(t, bytes.Repeat([]byte{byte(i)}, 10), v, "value %d", i)
+	}
+}
+
+func TestStreamReader_BoundedMemory(t *testing.T) {
+	value := append([]byte{0xb8, 100}, bytes.Repeat([]byte{0xfa}, 100)...)
+	const values = 1 << 20 // ~100MB streamed through a 4KB window
+	s := NewStreamReader(&repeatReader{data: value, n: values * len(value)}, 4096)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	for i := 0; i < values; i++ {
+		v, err := s.DecodeNextValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(v) != 100 {
+			t.Fatalf("value %d has length %d", i, len(v))
+		}
+	}
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	assert.Equal(t, uint64(values*len(value)), s.Pos())
+	// the refills allocate the whole stream, but the discarded windows are released
+	assert.Greater(t, after.TotalAlloc-before.TotalAlloc, uint64(values*100))
+	assert.Less(t, int64(after.HeapAlloc)-int64(before.HeapAlloc), int64(1<<20))
+}
+
+// stuckReader returns its bytes and then no bytes and no error forever
+type stuckReader struct {
+	data []byte
+}
+
+func (r *stuckReader) Read(p []byte) (int, error) {
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestStreamReader_NoProgress(t *testing.T) {
+	big := []byte{0xb9, 0x10, 0x00} // a string of 4096 bytes, bigger than the window
+	tests := []struct {
+		Name string
+		Data []byte
+		Skip bool
+	}{
+		{Name: "value", Data: []byte{0x8a, 1, 2}},
+		{Name: "value bigger than the window", Data: big},
+		{Name: "skip", Data: big, Skip: true},
+	}
+	for _, test := range tests {
+		s := NewStreamReader(&stuckReader{data: test.Data}, 32)
+		var err error
+		if test.Skip {
+			var size uint64
+			size, err = s.ReadValueSize()
+			assert.NoError(t, err, test.Name)
+			err = s.Skip(size)
+		} else {
+			_, err = s.DecodeNextValue()
+		}
+		assert.ErrorIs(t, err, io.ErrNoProgress, test.Name)
+	}
+}
@@ -16,7 +16,7 @@ func DecodePoolTxsPacket(r *reader.RlpReader) ([]*SimpleTx, error) {
 	}
 	_, err = r.DecodeUint64()
 	if err != nil {
-		return []*SimpleTx{}, err
+		return []*SimpleTx{}, errors.WithField(err, "requestId")
 	}
 	return DecodeTxsPacket(r)
 }
@@ -29,14 +29,14 @@ func DecodeTxsPacket(r *reader.RlpReader) ([]*SimpleTx, error) {
 		return txs, err
 	}
 	cPos := r.Pos()
-	for r.Pos()-cPos < listSize {
+	for i := 0; r.Pos()-cPos < listSize; i++ {
 		tx, err := DecodeTx(r)
 
 		if err != nil {
 			if errors.Is(err, errors.ErrTxTypeNotSupported) {
 				continue
 			}
-			return txs, err
+			return txs, errors.WithTxIndex(err, i)
 		}
 		txs = append(txs, tx)
 	}
@@ -46,7 +46,11 @@ func DecodeTxsPacket(r *reader.RlpReader) ([]*SimpleTx, error) {
 // DecodeTx decodes a transaction from the provided RLP-encoded byte array and returns a SimpleTx instance.
 func DecodeTx(r *reader.RlpReader) (*SimpleTx, error) {
 	if r.IsNextValAList() {
-		return DecodeLegacyTx(r)
+		tx, err := DecodeLegacyTx(r)
+		if err != nil {
+			return nil, errors.WithTxType(err, types.LegacyTxType)
+		}
+		return tx, nil
 	} else {
 		return DecodeModernTx(r)
 	}
@@ -60,6 +64,9 @@ func DecodeLegacyTx(r *reader.RlpReader) (*SimpleTx, error) {
 	if err != nil {
 		return nil, err
 	}
+	if !r.EnoughBytes(bytesLength) {
+		return nil, errors.AtOffset(io.EOF, cPos)
+	}
 	newPos := r.Pos() - cPos
 	rlpBytes := r.GetBytes(cPos, cPos+newPos+bytesLength)
 	return &SimpleTx{
@@ -75,46 +82,65 @@ func DecodeModernTx(r *reader.RlpReader) (*SimpleTx, error) {
 	// we already assume that this is another tx type so we just read how many bytes it has
 	valLength, err := r.ReadValueSize()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	// check that there are enough bytes to read the tx
 	if !r.EnoughBytes(valLength) {
-		return nil, io.EOF
+		return nil, errors.AtOffset(io.EOF, pos)
 	}
 	// starting point just indicates from which byte from the rlp needs to read for the tx hash
 	startPoint := r.Pos() - pos
 
 	rlpBytes := r.GetBytes(pos, pos+valLength+startPoint)
 	txType, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
 	switch txType {
 	case types.AccessListTxType, types.DynamicFeeTxType, types.SetCodeTxType, types.BlobTxType:
 		{
 			txListSize, err := r.ReadListSize()
 			if err != nil {
-				return nil, err
+				return nil, errors.WithTxType(err, int(txType))
 			}
 
 			currentPos := r.Pos()
-			chainId, err := r.DecodeNextValue()
+			chainId, err := r.DecodeNextUint()
+			if err != nil {
+				return nil, errors.WithTxType(errors.WithField(err, "chainId"), int(txType))
+			}
 			bytesRead := r.Pos() - currentPos
+			if bytesRead > txListSize {
+				err = errors.ErrUnexpectedLength.WithMessagef("chainId takes %d bytes of a %d bytes list", bytesRead, txListSize)
+				return nil, errors.WithTxType(errors.WithField(errors.AtOffset(err, currentPos), "chainId"), int(txType))
+			}
+			err = r.Skip(txListSize - bytesRead)
+			if err != nil {
+				return nil, errors.WithTxType(err, int(txType))
+			}
+			// the tx payload must take exactly the bytes of the rlp string that wraps it
+			if end := pos + uint64(len(rlpBytes)); r.Pos() != end {
+				err = errors.ErrUnexpectedLength.WithMessagef("tx payload ends at %d instead of %d", r.Pos(), end)
+				return nil, errors.WithTxType(errors.AtOffset(err, pos), int(txType))
+			}
 
 			return &SimpleTx{
 				TxType:     txType,
 				RLPBytes:   rlpBytes,
 				ChainId:    new(big.Int).SetBytes(chainId),
 				startPoint: startPoint,
-			}, r.Skip(txListSize - bytesRead)
+			}, nil
 		}
 	default:
 		// up to this point we have read that it is not a supported tx,
 		// so the next thing to do is read the list length and skip the nbytes
 		txListSize, err := r.ReadListSize()
 		if err != nil {
-			return nil, err
+			return nil, errors.WithTxType(err, int(txType))
 		}
 		err = r.Skip(txListSize)
 		if err != nil {
-			return nil, err
+			return nil, errors.WithTxType(err, int(txType))
 		}
 		return nil, errors.ErrTxTypeNotSupported
 	}
@@ -0,0 +1,124 @@
+package simpleTx
+
+import (
+	"github.com/1aBcD1234aBcD1/prlp/reader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+// fuzzSeedTxsForTests returns a signed tx of some of the supported types, used to build the seed packets of the
+// fuzz targets
+func fuzzSeedTxsForTests(f testing.TB) []*types.Transaction {
+	privKey, err := crypto.GenerateKey()
+	if err != nil {
+		f.Fatalf("Failed to generate private key: %v", err)
+	}
+	to := common.HexToAddress("0x2f2a2543b76a4166549f7aab2e75bef0aefc5b0f")
+	txsData := []types.TxData{
+		&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1e9), Gas: 21000, To: &to, Value: big.NewInt(1)},
+		&types.DynamicFeeTx{ChainID: big.NewInt(8453), Nonce: 2, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1e9),
+			Gas: 21000, To: &to},
+		&types.BlobTx{ChainID: uint256.NewInt(8453), Nonce: 3, GasTipCap: uint256.NewInt(1), GasFeeCap: uint256.NewInt(1e9),
+			Gas: 21000, To: to, BlobFeeCap: uint256.NewInt(1), BlobHashes: []common.Hash{{0x01}}},
+	}
+	txs := make([]*types.Transaction, 0, len(txsData))
+	for _, txData := range txsData {
+		tx, err := types.SignNewTx(privKey, types.LatestSignerForChainID(big.NewInt(8453)), txData)
+		if err != nil {
+			f.Fatalf("Failed to sign tx: %v", err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+func FuzzDecodeTxsPacket(f *testing.F) {
+	txs := fuzzSeedTxsForTests(f)
+	for i := range txs {
+		b, err := rlp.EncodeToBytes(txs[:i+1])
+		if err != nil {
+			f.Fatalf("Failed to RLP encode transactions: %v", err)
+		}
+		f.Add(b)
+	}
+	// truncated size of a typed tx
+	f.Add([]byte{0xc1, 0xb9})
+	// legacy tx list longer than the packet
+	f.Add([]byte{0xc2, 0xf8, 0xff})
+	// chainId longer than the typed tx list
+	f.Add([]byte{0xc6, 0x85, 0x02, 0xc1, 0x82, 0x01, 0x02})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, strict := range []bool{false, true} {
+			r := reader.NewReader(data)
+			r.SetStrict(strict)
+			decoded, _ := DecodeTxsPacket(r)
+			for _, tx := range decoded {
+				tx.Hash()
+			}
+		}
+	})
+}
+
+func FuzzDecodePoolTxsPacket(f *testing.F) {
+	txs := fuzzSeedTxsForTests(f)
+	for i := range txs {
+		b, err := rlp.EncodeToBytes(eth.PooledTransactionsPacket{
+			RequestId:                  uint64(i),
+			PooledTransactionsResponse: txs[:i+1],
+		})
+		if err != nil {
+			f.Fatalf("Failed to RLP encode transactions: %v", err)
+		}
+		f.Add(b)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, strict := range []bool{false, true} {
+			r := reader.NewReader(data)
+			r.SetStrict(strict)
+			decoded, _ := DecodePoolTxsPacket(r)
+			for _, tx := range decoded {
+				tx.Hash()
+			}
+		}
+	})
+}
+
+// FuzzDecodeTx decodes single txs in their wire encoding. For every tx that go-ethereum accepts prlp must decode the
+// same hash and chain id, keeping the rlp bytes as they are.
+func FuzzDecodeTx(f *testing.F) {
+	txs := fuzzSeedTxsForTests(f)
+	for _, tx := range txs {
+		b, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			f.Fatalf("Failed to RLP encode transaction: %v", err)
+		}
+		f.Add(b)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		want := new(types.Transaction)
+		if err := rlp.DecodeBytes(data, want); err != nil {
+			// go-ethereum rejects it, we only check that decoding it does not panic
+			_, _ = DecodeTx(reader.NewReader(data))
+			return
+		}
+		r := reader.NewStrictReader(data)
+		got, err := DecodeTx(r)
+		if !assert.NoError(t, err, "rejected a tx accepted by go-ethereum") {
+			return
+		}
+		assert.Equal(t, uint64(0), r.Len(), "not all data consumed")
+		assert.Equal(t, data, got.RLPBytes, "rlp bytes not equal")
+		assert.Equal(t, want.Type(), got.TxType, "type not equal")
+		assert.Equal(t, want.Hash(), got.Hash(), "hash not equal")
+		if want.Type() != types.LegacyTxType {
+			assert.Equal(t, want.ChainId().String(), got.ChainId.String(), "chain id not equal")
+		}
+	})
+}
@@ -21,3 +21,20 @@ func (tx *SimpleTx) Hash() common.Hash {
 	}
 	return common.BytesToHash(tx.hash)
 }
+
+// Detach copies the RLPBytes of the decoded tx, which point to the decoded bytes, so the tx stays valid after they
+// are modified or reused. To decode txs that never point to the decoded bytes see reader.NewCopyReader.
+func (tx *SimpleTx) Detach() {
+	tx.RLPBytes = common.CopyBytes(tx.RLPBytes)
+}
+
+// Clone returns a copy of the tx that shares no memory with it
+func (tx *SimpleTx) Clone() *SimpleTx {
+	c := *tx
+	c.Detach()
+	c.hash = common.CopyBytes(tx.hash)
+	if tx.ChainId != nil {
+		c.ChainId = new(big.Int).Set(tx.ChainId)
+	}
+	return &c
+}
@@ -1,7 +1,9 @@
 package simpleTx
 
 import (
+	"bytes"
 	"fmt"
+	"github.com/1aBcD1234aBcD1/prlp/errors"
 	reader "github.com/1aBcD1234aBcD1/prlp/reader"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -138,3 +140,58 @@ func TestDecodeRLPPooledTxsPacket(t *testing.T) {
 	assert.Equal(t, uint64(0), r.Len(), "not all data consumed")
 
 }
+
+func TestDecodeTxsPacketErrorPosition(t *testing.T) {
+	legacyTx, _ := rlp.EncodeToBytes([]rlp.RawValue{{0x05}, {0x01}, {0x82, 0x52, 0x08}, {0x80}, {0x80}, {0x80}, {0x25}, {0x01}, {0x01}})
+	// chainId with a leading zero byte
+	badChainId := []byte{0x82, 0x00, 0x38}
+	payload, _ := rlp.EncodeToBytes([]rlp.RawValue{badChainId, {0x01}})
+	dynamicFeeTx, _ := rlp.EncodeToBytes(append([]byte{types.DynamicFeeTxType}, payload...))
+	packet, _ := rlp.EncodeToBytes([]rlp.RawValue{legacyTx, dynamicFeeTx})
+
+	_, err := DecodeTxsPacket(reader.NewReader(packet))
+	assert.NoError(t, err)
+
+	_, err = DecodeTxsPacket(reader.NewStrictReader(packet))
+	assert.True(t, errors.Is(err, errors.ErrIntLeadingZero), "unexpected error %v", err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("tx[1] type=2 field=chainId at offset %d", bytes.Index(packet, badChainId)))
+}
+
+func TestSimpleTx_Detach(t *testing.T) {
+	txs := fuzzSeedTxsForTests(t)
+	packet, err := rlp.EncodeToBytes(txs)
+	assert.NoError(t, err)
+
+	decode := func(r *reader.RlpReader) []*SimpleTx {
+		decoded, err := DecodeTxsPacket(r)
+		assert.NoError(t, err)
+		assert.Len(t, decoded, len(txs))
+		return decoded
+	}
+	aliased := decode(reader.NewReader(packet))
+	detached := decode(reader.NewReader(packet))
+	cloned := decode(reader.NewReader(packet))
+	copied := decode(reader.NewCopyReader(packet))
+	for i := range txs {
+		detached[i].Detach()
+		cloned[i] = cloned[i].Clone()
+	}
+	// reuse the buffer
+	for i := range packet {
+		packet[i] = 0xff
+	}
+	for i, tx := range txs {
+		want, err := tx.MarshalBinary()
+		assert.NoError(t, err)
+		assert.NotEqual(t, want, aliased[i].RLPBytes)
+		for _, got := range []*SimpleTx{detached[i], cloned[i], copied[i]} {
+			if tx.Type() == types.LegacyTxType {
+				assert.Equal(t, want, got.RLPBytes)
+			} else {
+				// typed txs keep the rlp string wrapping them
+				assert.Equal(t, want, got.RLPBytes[got.startPoint:])
+			}
+			assert.Equal(t, tx.Hash(), got.Hash())
+		}
+	}
+}